@@ -2,7 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
@@ -13,7 +20,11 @@ import (
 	"github.com/cpchain-network/gas-oracle/common/opio"
 	"github.com/cpchain-network/gas-oracle/config"
 	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/event"
+	"github.com/cpchain-network/gas-oracle/migrations"
 	grpc2 "github.com/cpchain-network/gas-oracle/services/grpc"
+	"github.com/cpchain-network/gas-oracle/services/jsonrpc"
+	"github.com/cpchain-network/gas-oracle/worker"
 )
 
 var (
@@ -26,46 +37,158 @@ var (
 	}
 	MigrationsFlag = &cli.StringFlag{
 		Name:    "migrations-dir",
-		Value:   "./migrations",
-		Usage:   "path to migrations folder",
+		Usage:   "path to a migrations folder to use instead of the migrations built into the binary",
 		EnvVars: []string{"GAS_ORACLE_MIGRATIONS_DIR"},
 	}
 )
 
+// loadConfig loads the config named by the config flag and validates it.
+func loadConfig(ctx *cli.Context) (*config.Config, error) {
+	cfg, err := config.New(ctx.String(ConfigFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
 func runOracle(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	log.Info("running gas oracle...")
-	cfg, err := config.New(ctx.String(ConfigFlag.Name))
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Error("failed to load config", "err", err)
 		return nil, err
 	}
-	return gas_oracle.NewGasOracle(ctx.Context, cfg, shutdown)
+	oracle, err := gas_oracle.NewGasOracle(ctx.Context, cfg, shutdown)
+	if err != nil {
+		return nil, err
+	}
+	oracle.WatchConfig(ctx.String(ConfigFlag.Name))
+	return oracle, nil
 }
 
 func runGRPCSever(ctx *cli.Context, _ context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	fmt.Println("running grpc services...")
-	cfg, err := config.New(ctx.String(ConfigFlag.Name))
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Error("config error", "err", err)
+		return nil, err
+	}
+
+	db, err := database.NewDB(ctx.Context, cfg.MasterDb)
+	if err != nil {
+		log.Error("new database fail", "err", err)
+		return nil, err
+	}
+
+	return grpc2.NewTokenPriceRpcService(newTokenPriceRpcConfig(cfg), db)
+}
+
+func newTokenPriceRpcConfig(cfg *config.Config) *grpc2.TokenPriceRpcConfig {
+	return &grpc2.TokenPriceRpcConfig{
+		Host:            cfg.Server.Host,
+		Port:            cfg.Server.Port,
+		HttpPort:        cfg.Server.HttpPort,
+		TLS:             cfg.Server.TLS,
+		Auth:            cfg.Auth,
+		Admin:           cfg.Admin,
+		RateLimit:       cfg.RateLimit,
+		Registry:        cfg,
+		QuoteCurrencies: cfg.QuoteCurrencyList(),
+		CacheTTL:        2 * longestLoopInterval(cfg),
+	}
+}
+
+// longestLoopInterval returns the longest pause between two fee or price
+// rounds, after which the in-process cache is no fresher than the database.
+func longestLoopInterval(cfg *config.Config) time.Duration {
+	longest := cfg.PriceLoopInterval
+	if longest == 0 {
+		longest = worker.DefaultLoopInterval
+	}
+	longest = max(longest, cfg.LoopInternal)
+	for _, rpc := range cfg.RPCs {
+		longest = max(longest, rpc.LoopInternal)
+	}
+	return longest
+}
+
+func runJsonRpcServer(ctx *cli.Context, _ context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log.Info("running json rpc services...")
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Error("config error", "err", err)
 		return nil, err
 	}
 
-	grpcServerCfg := &grpc2.TokenPriceRpcConfig{
-		Host: cfg.Server.Host,
-		Port: cfg.Server.Port,
+	db, err := database.NewDB(ctx.Context, cfg.MasterDb)
+	if err != nil {
+		log.Error("new database fail", "err", err)
+		return nil, err
+	}
+
+	return jsonrpc.NewFeeRpcService(newFeeRpcConfig(cfg), db)
+}
+
+func newFeeRpcConfig(cfg *config.Config) *jsonrpc.FeeRpcConfig {
+	return &jsonrpc.FeeRpcConfig{
+		Host:     cfg.JsonRpc.Host,
+		Port:     cfg.JsonRpc.Port,
+		Registry: cfg,
+	}
+}
+
+// runAll runs the indexer and the api servers in one process on a shared
+// database pool, with stored fees and prices delivered to the servers in-process.
+func runAll(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
+	log.Info("running gas oracle with all services...")
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return nil, err
 	}
 
 	db, err := database.NewDB(ctx.Context, cfg.MasterDb)
 	if err != nil {
 		log.Error("new database fail", "err", err)
+		return nil, err
+	}
+	bus := event.NewBus()
+
+	supervisor := cliapp.NewSupervisor()
+	supervisor.Add("database", cliapp.CloserLifecycle(db.Close))
+
+	oracle, err := gas_oracle.NewGasOracleFromDB(ctx.Context, cfg, db, bus, shutdown)
+	if err != nil {
+		return nil, errors.Join(err, supervisor.Abort(ctx.Context))
+	}
+	oracle.WatchConfig(ctx.String(ConfigFlag.Name))
+	supervisor.Add("indexer", oracle)
+
+	rpcService, err := grpc2.NewTokenPriceRpcService(newTokenPriceRpcConfig(cfg), db)
+	if err != nil {
+		return nil, errors.Join(err, supervisor.Abort(ctx.Context))
+	}
+	rpcService.SubscribeEvents(bus)
+	supervisor.Add("grpc", rpcService)
+
+	if cfg.JsonRpc.Port != 0 {
+		feeRpcService, err := jsonrpc.NewFeeRpcService(newFeeRpcConfig(cfg), db)
+		if err != nil {
+			return nil, errors.Join(err, supervisor.Abort(ctx.Context))
+		}
+		supervisor.Add("jsonrpc", feeRpcService)
 	}
 
-	return grpc2.NewTokenPriceRpcService(grpcServerCfg, db)
+	return supervisor, nil
 }
 
-func runMigrations(ctx *cli.Context) error {
+// withMigrations connects to the database and hands fn the migrations to run:
+// the ones embedded in the binary, or those of --migrations-dir when set.
+func withMigrations(ctx *cli.Context, fn func(db *database.DB, fsys fs.FS) error) error {
 	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
-	log.Info("running migrations...")
 	cfg, err := config.New(ctx.String(ConfigFlag.Name))
 	if err != nil {
 		log.Error("failed to load config", "err", err)
@@ -82,11 +205,86 @@ func runMigrations(ctx *cli.Context) error {
 			return
 		}
 	}(db)
-	err = db.ExecuteSQLMigration(ctx.String(MigrationsFlag.Name))
+
+	var fsys fs.FS = migrations.FS
+	if dir := ctx.String(MigrationsFlag.Name); dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	return fn(db, fsys)
+}
+
+func runMigrateUp(ctx *cli.Context) error {
+	log.Info("running migrations...")
+	return withMigrations(ctx, func(db *database.DB, fsys fs.FS) error {
+		applied, err := db.MigrateUp(fsys)
+		if err != nil {
+			return err
+		}
+		log.Info("running migrations success", "applied", len(applied))
+		return nil
+	})
+}
+
+func runMigrateDown(ctx *cli.Context) error {
+	n := 1
+	if ctx.Args().Present() {
+		var err error
+		n, err = strconv.Atoi(ctx.Args().First())
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of migrations to revert: %s", ctx.Args().First())
+		}
+	}
+	log.Info("reverting migrations...", "count", n)
+	return withMigrations(ctx, func(db *database.DB, fsys fs.FS) error {
+		reverted, err := db.MigrateDown(fsys, n)
+		if err != nil {
+			return err
+		}
+		log.Info("reverting migrations success", "reverted", len(reverted))
+		return nil
+	})
+}
+
+func runMigrateStatus(ctx *cli.Context) error {
+	return withMigrations(ctx, func(db *database.DB, fsys fs.FS) error {
+		statuses, err := db.MigrationStatus(fsys)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(ctx.App.Writer, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state = "applied"
+				appliedAt = time.Unix(int64(status.AppliedAt), 0).UTC().Format(time.RFC3339)
+			}
+			switch {
+			case status.Missing:
+				state += " (file missing)"
+			case status.Modified:
+				state += " (modified)"
+			}
+			fmt.Fprintf(w, "%05d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	})
+}
+
+func runConfigCheck(ctx *cli.Context) error {
+	path := ctx.String(ConfigFlag.Name)
+	cfg, err := config.New(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to load config %s: %w", path, err)
 	}
-	log.Info("running migrations and create table from template success")
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(ctx.App.Writer, "config %s is invalid:\n", path)
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(ctx.App.Writer, "  - %s\n", problem)
+		}
+		return cli.Exit("", 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "config %s is valid\n", path)
 	return nil
 }
 
@@ -104,6 +302,18 @@ func newCli() *cli.App {
 				Description: "Runs the gprc service",
 				Action:      cliapp.LifecycleCmd(runGRPCSever),
 			},
+			{
+				Name:        "all",
+				Flags:       flags,
+				Description: "Runs the indexing service and the api servers in one process",
+				Action:      cliapp.LifecycleCmd(runAll),
+			},
+			{
+				Name:        "jsonrpc",
+				Flags:       flags,
+				Description: "Runs the ethereum json rpc fee facade",
+				Action:      cliapp.LifecycleCmd(runJsonRpcServer),
+			},
 			{
 				Name:        "index",
 				Flags:       flags,
@@ -113,8 +323,41 @@ func newCli() *cli.App {
 			{
 				Name:        "migrate",
 				Flags:       migrationFlags,
-				Description: "Runs the database migrations",
-				Action:      runMigrations,
+				Description: "Applies the pending database migrations",
+				Action:      runMigrateUp,
+				Subcommands: []*cli.Command{
+					{
+						Name:        "up",
+						Flags:       migrationFlags,
+						Description: "Applies the pending database migrations",
+						Action:      runMigrateUp,
+					},
+					{
+						Name:        "down",
+						Flags:       migrationFlags,
+						ArgsUsage:   "[N]",
+						Description: "Reverts the last N applied migrations, 1 by default",
+						Action:      runMigrateDown,
+					},
+					{
+						Name:        "status",
+						Flags:       migrationFlags,
+						Description: "Lists the applied and pending migrations",
+						Action:      runMigrateStatus,
+					},
+				},
+			},
+			{
+				Name:        "config",
+				Description: "Inspects the config file",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "check",
+						Flags:       flags,
+						Description: "Validates the config file and reports every problem found",
+						Action:      runConfigCheck,
+					},
+				},
 			},
 			{
 				Name:        "version",
@@ -1,6 +1,10 @@
 package bigint
 
-import "math/big"
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
 
 var (
 	Zero = big.NewInt(0)
@@ -31,3 +35,25 @@ func WeiToETH(wei *big.Int) *big.Float {
 	f.SetString(wei.String())
 	return f.Quo(f, big.NewFloat(1e18))
 }
+
+// FormatDecimal formats r in decimal notation rounded to at most scale
+// fractional digits, without trailing zeros.
+func FormatDecimal(r *big.Rat, scale int) string {
+	s := r.FloatString(scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// ParseDecimal parses a decimal number such as "1.25" exactly.
+func ParseDecimal(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal number %q", s)
+	}
+	return r, nil
+}
@@ -27,3 +27,16 @@ func TestClamp(t *testing.T) {
 	require.False(t, end == result)
 	require.Equal(t, uint64(5), result.Uint64())
 }
+
+func TestFormatDecimal(t *testing.T) {
+	price, err := ParseDecimal("0.000000012345")
+	require.NoError(t, err)
+	require.Equal(t, "0.000000012345", FormatDecimal(price, 18))
+	require.Equal(t, "0.00000001", FormatDecimal(price, 8))
+	require.Equal(t, "0", FormatDecimal(price, 6))
+	require.Equal(t, "1500", FormatDecimal(big.NewRat(1500, 1), 18))
+	require.Equal(t, "0.5", FormatDecimal(big.NewRat(1, 2), 18))
+
+	_, err = ParseDecimal("1.2.3")
+	require.Error(t, err)
+}
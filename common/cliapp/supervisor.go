@@ -0,0 +1,96 @@
+package cliapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type namedLifecycle struct {
+	name      string
+	lifecycle Lifecycle
+}
+
+// Supervisor runs several services as a single Lifecycle.
+// Services start in the order they were added and stop in reverse order,
+// so a service may depend on everything added before it.
+type Supervisor struct {
+	services []namedLifecycle
+	started  int
+	stopped  atomic.Bool
+}
+
+func NewSupervisor() *Supervisor {
+	return &Supervisor{}
+}
+
+func (s *Supervisor) Add(name string, lifecycle Lifecycle) {
+	s.services = append(s.services, namedLifecycle{name: name, lifecycle: lifecycle})
+}
+
+// Start starts every service in order. If one fails, the services already
+// started are stopped again before the error is returned.
+func (s *Supervisor) Start(ctx context.Context) error {
+	for _, service := range s.services {
+		log.Info("starting service", "service", service.name)
+		if err := service.lifecycle.Start(ctx); err != nil {
+			startErr := fmt.Errorf("failed to start %s: %w", service.name, err)
+			return errors.Join(startErr, s.Stop(ctx))
+		}
+		s.started++
+	}
+	return nil
+}
+
+// Stop stops the started services in reverse order, continuing past failures.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	var result error
+	for i := s.started - 1; i >= 0; i-- {
+		service := s.services[i]
+		log.Info("stopping service", "service", service.name)
+		if err := service.lifecycle.Stop(ctx); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to stop %s: %w", service.name, err))
+		}
+	}
+	s.started = 0
+	s.stopped.Store(true)
+	return result
+}
+
+// Abort stops every added service in reverse order, whether started or not,
+// to release what was built when assembling the services fails half way.
+func (s *Supervisor) Abort(ctx context.Context) error {
+	s.started = len(s.services)
+	return s.Stop(ctx)
+}
+
+func (s *Supervisor) Stopped() bool {
+	return s.stopped.Load()
+}
+
+type closerLifecycle struct {
+	close   func() error
+	stopped atomic.Bool
+}
+
+// CloserLifecycle wraps a shared resource, such as a database handle, so a
+// Supervisor closes it once every service added after it has stopped.
+func CloserLifecycle(close func() error) Lifecycle {
+	return &closerLifecycle{close: close}
+}
+
+func (c *closerLifecycle) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *closerLifecycle) Stop(ctx context.Context) error {
+	c.stopped.Store(true)
+	return c.close()
+}
+
+func (c *closerLifecycle) Stopped() bool {
+	return c.stopped.Load()
+}
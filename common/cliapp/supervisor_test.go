@@ -0,0 +1,99 @@
+package cliapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingService struct {
+	name     string
+	events   *[]string
+	startErr error
+	stopErr  error
+	stopped  bool
+}
+
+func (s *recordingService) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start "+s.name)
+	return s.startErr
+}
+
+func (s *recordingService) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop "+s.name)
+	s.stopped = true
+	return s.stopErr
+}
+
+func (s *recordingService) Stopped() bool {
+	return s.stopped
+}
+
+func TestSupervisorStopsInReverseOrder(t *testing.T) {
+	var events []string
+	supervisor := NewSupervisor()
+	for _, name := range []string{"database", "indexer", "grpc"} {
+		supervisor.Add(name, &recordingService{name: name, events: &events})
+	}
+
+	require.NoError(t, supervisor.Start(context.Background()))
+	require.NoError(t, supervisor.Stop(context.Background()))
+	require.True(t, supervisor.Stopped())
+	require.Equal(t, []string{
+		"start database", "start indexer", "start grpc",
+		"stop grpc", "stop indexer", "stop database",
+	}, events)
+}
+
+func TestSupervisorStopsStartedServicesOnStartFailure(t *testing.T) {
+	var events []string
+	startErr := errors.New("port taken")
+	supervisor := NewSupervisor()
+	supervisor.Add("database", &recordingService{name: "database", events: &events})
+	supervisor.Add("indexer", &recordingService{name: "indexer", events: &events})
+	supervisor.Add("grpc", &recordingService{name: "grpc", events: &events, startErr: startErr})
+	supervisor.Add("jsonrpc", &recordingService{name: "jsonrpc", events: &events})
+
+	err := supervisor.Start(context.Background())
+	require.ErrorIs(t, err, startErr)
+	require.ErrorContains(t, err, "failed to start grpc")
+	require.Equal(t, []string{
+		"start database", "start indexer", "start grpc",
+		"stop indexer", "stop database",
+	}, events)
+}
+
+func TestSupervisorStopContinuesPastFailures(t *testing.T) {
+	var events []string
+	stopErr := errors.New("close fail")
+	supervisor := NewSupervisor()
+	supervisor.Add("database", &recordingService{name: "database", events: &events})
+	supervisor.Add("indexer", &recordingService{name: "indexer", events: &events, stopErr: stopErr})
+
+	require.NoError(t, supervisor.Start(context.Background()))
+	err := supervisor.Stop(context.Background())
+	require.ErrorIs(t, err, stopErr)
+	require.Equal(t, []string{"start database", "start indexer", "stop indexer", "stop database"}, events)
+
+	closed := false
+	closer := CloserLifecycle(func() error {
+		closed = true
+		return nil
+	})
+	require.NoError(t, closer.Stop(context.Background()))
+	require.True(t, closed)
+	require.True(t, closer.Stopped())
+}
+
+func TestSupervisorAbortStopsUnstartedServices(t *testing.T) {
+	var events []string
+	supervisor := NewSupervisor()
+	supervisor.Add("database", &recordingService{name: "database", events: &events})
+	supervisor.Add("indexer", &recordingService{name: "indexer", events: &events})
+
+	require.NoError(t, supervisor.Abort(context.Background()))
+	require.True(t, supervisor.Stopped())
+	require.Equal(t, []string{"stop indexer", "stop database"}, events)
+}
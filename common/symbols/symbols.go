@@ -0,0 +1,87 @@
+// Package symbols maps the names a token goes by to the oracle symbol its
+// prices are stored under: the symbol itself in any case, its aliases and its
+// contract address on each chain. It also maps the symbol back to the symbol
+// queried from each upstream source.
+package symbols
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+type addressKey struct {
+	chainId uint64
+	address common.Address
+}
+
+// Map resolves token names and addresses to oracle symbols. A Map is not
+// changed after NewMap and may be shared.
+type Map struct {
+	symbols   map[string]config.Symbols
+	names     map[string]string
+	addresses map[addressKey]string
+}
+
+// NewMap indexes symbolList. Later entries win when a name or address is
+// claimed twice, which the config validation rejects.
+func NewMap(symbolList []config.Symbols) *Map {
+	m := &Map{
+		symbols:   make(map[string]config.Symbols, len(symbolList)),
+		names:     make(map[string]string, len(symbolList)),
+		addresses: make(map[addressKey]string),
+	}
+	for _, symbol := range symbolList {
+		m.symbols[symbol.Name] = symbol
+		m.names[strings.ToLower(symbol.Name)] = symbol.Name
+		for _, alias := range symbol.Aliases {
+			m.names[strings.ToLower(alias)] = symbol.Name
+		}
+		for _, address := range symbol.Addresses {
+			if common.IsHexAddress(address.Address) {
+				m.addresses[addressKey{address.ChainId, common.HexToAddress(address.Address)}] = symbol.Name
+			}
+		}
+	}
+	return m
+}
+
+// Resolve returns the symbol named by name or one of its aliases.
+func (m *Map) Resolve(name string) (string, bool) {
+	symbol, ok := m.names[strings.ToLower(name)]
+	return symbol, ok
+}
+
+// ResolveAddress returns the symbol of the token contract at address on chainId.
+func (m *Map) ResolveAddress(chainId uint64, address common.Address) (string, bool) {
+	symbol, ok := m.addresses[addressKey{chainId, address}]
+	return symbol, ok
+}
+
+// ResolveToken returns the symbol of token on chainId, where token is a
+// contract address or a symbol name or alias.
+func (m *Map) ResolveToken(chainId uint64, token string) (string, bool) {
+	if common.IsHexAddress(token) {
+		return m.ResolveAddress(chainId, common.HexToAddress(token))
+	}
+	return m.Resolve(token)
+}
+
+// Canonical returns the symbol name resolves to, or name itself when it is
+// no known symbol, such as a quote currency or an intermediate pool token.
+func (m *Map) Canonical(name string) string {
+	if symbol, ok := m.Resolve(name); ok {
+		return symbol
+	}
+	return name
+}
+
+// SkyeyeSymbol returns the symbol queried from skyeye for symbol.
+func (m *Map) SkyeyeSymbol(symbol string) string {
+	if entry, ok := m.symbols[symbol]; ok && entry.SkeyeSymbol != "" {
+		return entry.SkeyeSymbol
+	}
+	return symbol
+}
@@ -0,0 +1,47 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+func TestMapResolve(t *testing.T) {
+	m := NewMap([]config.Symbols{
+		{Name: "eth", Decimal: 18, Aliases: []string{"WETH"}, Addresses: []config.TokenAddress{
+			{ChainId: 1, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+			{ChainId: 10, Address: "0x4200000000000000000000000000000000000006"},
+		}},
+		{Name: "cp", Decimal: 18, SkeyeSymbol: "cpchain"},
+	})
+
+	for _, name := range []string{"eth", "ETH", "weth", "WETH"} {
+		symbol, ok := m.Resolve(name)
+		require.True(t, ok, name)
+		require.Equal(t, "eth", symbol, name)
+	}
+	_, ok := m.Resolve("usd")
+	require.False(t, ok)
+	require.Equal(t, "USD", m.Canonical("USD"))
+	require.Equal(t, "eth", m.Canonical("WETH"))
+
+	symbol, ok := m.ResolveAddress(10, common.HexToAddress("0x4200000000000000000000000000000000000006"))
+	require.True(t, ok)
+	require.Equal(t, "eth", symbol)
+	// the same contract on another chain is another token
+	_, ok = m.ResolveAddress(1, common.HexToAddress("0x4200000000000000000000000000000000000006"))
+	require.False(t, ok)
+
+	symbol, ok = m.ResolveToken(1, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	require.True(t, ok)
+	require.Equal(t, "eth", symbol)
+	symbol, ok = m.ResolveToken(1, "Weth")
+	require.True(t, ok)
+	require.Equal(t, "eth", symbol)
+
+	require.Equal(t, "cpchain", m.SkyeyeSymbol("cp"))
+	require.Equal(t, "eth", m.SkyeyeSymbol("eth"))
+}
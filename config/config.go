@@ -1,18 +1,32 @@
 package config
 
 import (
-	"io/ioutil"
+	"fmt"
+	"os"
+	"reflect"
 	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
+type TLS struct {
+	CertFile          string `yaml:"cert_file"`
+	KeyFile           string `yaml:"key_file"`
+	ClientCAFile      string `yaml:"client_ca_file"`
+	RequireClientCert bool   `yaml:"require_client_cert"`
+}
+
 type Server struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	HttpPort int    `yaml:"http_port"`
+	TLS      TLS    `yaml:"tls"`
 }
 
+// Database selects a storage driver: postgres (the default), sqlite with
+// db_name as the database file, or memory, which keeps nothing across restarts.
 type Database struct {
+	Driver     string `yaml:"driver"`
 	DbHost     string `yaml:"db_host"`
 	DbPort     int    `yaml:"db_port"`
 	DbName     string `yaml:"db_name"`
@@ -25,30 +39,172 @@ type RPC struct {
 	ChainId     uint64 `yaml:"chain_id"`
 	NativeToken string `yaml:"native_token"`
 	Decimal     uint8  `yaml:"decimal"`
+	ChainName   string `yaml:"chain_name"`
+	// BackupRpcUrls are dialed in order when rpc_url is unreachable
+	BackupRpcUrls []string `yaml:"backup_rpc_urls"`
+	// BackOffset and LoopInternal override the global values for this chain when set
+	BackOffset   uint64        `yaml:"back_offset"`
+	LoopInternal time.Duration `yaml:"loop_internal"`
+}
+
+// RpcUrls returns rpc_url followed by the backup urls.
+func (r *RPC) RpcUrls() []string {
+	return append([]string{r.RpcUrl}, r.BackupRpcUrls...)
+}
+
+// DexPool is an on-chain Uniswap pool pricing token0 in token1. Tokens are
+// named like symbols or quote currencies, their decimals are read on-chain.
+// Unnamed tokens are the symbols whose addresses match the pool tokens.
+type DexPool struct {
+	ChainId uint64 `yaml:"chain_id"`
+	Address string `yaml:"address"`
+	// Version is v2 (getReserves, the default) or v3 (slot0 or observe)
+	Version string `yaml:"version"`
+	Token0  string `yaml:"token0"`
+	Token1  string `yaml:"token1"`
+	// TwapWindow averages a v3 price over this window instead of reading slot0
+	TwapWindow time.Duration `yaml:"twap_window"`
+}
+
+// ChainlinkFeed is an AggregatorV3Interface pricing base in quote.
+type ChainlinkFeed struct {
+	ChainId uint64 `yaml:"chain_id"`
+	Address string `yaml:"address"`
+	Base    string `yaml:"base"`
+	Quote   string `yaml:"quote"`
+	// MaxAge rejects rounds updated longer ago, one hour when unset
+	MaxAge time.Duration `yaml:"max_age"`
+	// Primary prefers the feed over skyeye, otherwise it is only a fallback
+	Primary bool `yaml:"primary"`
+}
+
+type Consumer struct {
+	Name     string    `yaml:"name"`
+	Token    string    `yaml:"token"`
+	Disabled bool      `yaml:"disabled"`
+	ExpireAt time.Time `yaml:"expire_at"`
+}
+
+type Auth struct {
+	Enable    bool       `yaml:"enable"`
+	Consumers []Consumer `yaml:"consumers"`
+}
+
+type ConsumerRateLimit struct {
+	Name       string  `yaml:"name"`
+	Rate       float64 `yaml:"rate"`
+	Burst      int     `yaml:"burst"`
+	DailyQuota uint64  `yaml:"daily_quota"`
+}
+
+type RateLimit struct {
+	Enable     bool                `yaml:"enable"`
+	Rate       float64             `yaml:"rate"`
+	Burst      int                 `yaml:"burst"`
+	DailyQuota uint64              `yaml:"daily_quota"`
+	Consumers  []ConsumerRateLimit `yaml:"consumers"`
 }
 
 type Symbols struct {
 	Name    string `yaml:"name"`
 	Decimal uint8  `yaml:"decimal"`
+	// SkeyeSymbol is the symbol queried from skyeye when it differs from name
+	SkeyeSymbol string `yaml:"skeye_symbol"`
+	// Aliases are other names of the token, such as weth for eth, that
+	// resolve to name wherever a symbol is accepted
+	Aliases []string `yaml:"aliases"`
+	// Addresses are the contracts of the token on each chain
+	Addresses []TokenAddress `yaml:"addresses"`
+}
+
+// TokenAddress is the contract of a token on one chain.
+type TokenAddress struct {
+	ChainId uint64 `yaml:"chain_id"`
+	Address string `yaml:"address"`
 }
 
+type Admin struct {
+	Tokens []string `yaml:"tokens"`
+}
+
+// LeaderElection lets several indexer replicas share the chains: each chain
+// and the price worker are sampled by the replica holding their lease.
+type LeaderElection struct {
+	Enable        bool          `yaml:"enable"`
+	LeaseTTL      time.Duration `yaml:"lease_ttl"`
+	RenewInterval time.Duration `yaml:"renew_interval"`
+	// Balance spreads the leases evenly over the live replicas instead of
+	// letting the first replica take them all
+	Balance bool `yaml:"balance"`
+}
+
+// PriceGuard rejects prices that move more than MaxDeviation percent from the
+// last stored price unless Confirmations consecutive samples agree. A zero
+// MaxDeviation only rejects non-positive prices.
+type PriceGuard struct {
+	MaxDeviation float64 `yaml:"max_deviation"`
+	// Window only compares against stored prices younger than it, any age when zero
+	Window time.Duration `yaml:"window"`
+	// Confirmations defaults to DefaultPriceConfirmations
+	Confirmations int `yaml:"confirmations"`
+}
+
+// DefaultPriceConfirmations is how many agreeing samples accept a large move
+// when the price guard sets none.
+const DefaultPriceConfirmations = 3
+
+// DefaultQuoteCurrency is what prices are quoted in when no quote_currencies
+// are configured.
+const DefaultQuoteCurrency = "USD"
+
 type Config struct {
-	SkyeyeUrl      string        `yaml:"skyeye_url"`
-	Server         Server        `yaml:"server"`
-	Symbols        []Symbols     `yaml:"symbols"`
-	RPCs           []*RPC        `yaml:"rpcs"`
-	Metrics        Server        `yaml:"metrics"`
-	MasterDb       Database      `yaml:"master_db"`
-	SlaveDb        Database      `yaml:"slave_db"`
-	SlaveDbEnable  bool          `yaml:"slave_db_enable"`
-	EnableApiCache bool          `yaml:"enable_api_cache"`
-	BackOffset     uint64        `yaml:"back_offset"`
-	LoopInternal   time.Duration `yaml:"loop_internal"`
+	SkyeyeUrl       string          `yaml:"skyeye_url"`
+	Server          Server          `yaml:"server"`
+	JsonRpc         Server          `yaml:"json_rpc"`
+	Auth            Auth            `yaml:"auth"`
+	Admin           Admin           `yaml:"admin"`
+	RateLimit       RateLimit       `yaml:"rate_limit"`
+	Symbols         []Symbols       `yaml:"symbols"`
+	QuoteCurrencies []string        `yaml:"quote_currencies"`
+	DexPools        []DexPool       `yaml:"dex_pools"`
+	ChainlinkFeeds  []ChainlinkFeed `yaml:"chainlink_feeds"`
+	PriceGuard      PriceGuard      `yaml:"price_guard"`
+	RPCs            []*RPC          `yaml:"rpcs"`
+	Metrics         Server          `yaml:"metrics"`
+	MasterDb        Database        `yaml:"master_db"`
+	SlaveDb         Database        `yaml:"slave_db"`
+	SlaveDbEnable   bool            `yaml:"slave_db_enable"`
+	EnableApiCache  bool            `yaml:"enable_api_cache"`
+	BackOffset      uint64          `yaml:"back_offset"`
+	LoopInternal    time.Duration   `yaml:"loop_internal"`
+	// PriceLoopInterval is the pause between pricing rounds, 5s when unset
+	PriceLoopInterval time.Duration `yaml:"price_loop_interval"`
+	// PriceFetchConcurrency bounds the parallel skyeye requests, 8 when unset
+	PriceFetchConcurrency int `yaml:"price_fetch_concurrency"`
+	// PriceRequestTimeout bounds each skyeye request, 10s when unset
+	PriceRequestTimeout time.Duration  `yaml:"price_request_timeout"`
+	LeaderElection      LeaderElection `yaml:"leader_election"`
+}
+
+// QuoteCurrencyList returns the currencies every symbol is priced in, the
+// first one being the default of the api, or the default one when none are set.
+func (c *Config) QuoteCurrencyList() []string {
+	if len(c.QuoteCurrencies) == 0 {
+		return []string{DefaultQuoteCurrency}
+	}
+	return c.QuoteCurrencies
 }
 
+// New loads the yaml config at path. ${VAR} references in its values are
+// expanded from the environment, `<field>_file` keys read the field from a
+// file, and GAS_ORACLE_* environment variables override any field last.
 func New(path string) (*Config, error) {
 	var config = new(Config)
-	data, err := ioutil.ReadFile(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = expandEnv(data)
 	if err != nil {
 		return nil, err
 	}
@@ -56,5 +212,16 @@ func New(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if err := applyFileRefs(reflect.ValueOf(config), raw); err != nil {
+		return nil, fmt.Errorf("failed to resolve config secret files: %w", err)
+	}
+	if err := applyEnvOverrides(reflect.ValueOf(config).Elem(), EnvPrefix, environ(EnvPrefix)); err != nil {
+		return nil, fmt.Errorf("failed to apply config env overrides: %w", err)
+	}
 	return config, nil
 }
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithEnvAndSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "db_password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("s3cret\n"), 0600))
+	apiKeyFile := filepath.Join(dir, "rpc_url")
+	require.NoError(t, os.WriteFile(apiKeyFile, []byte("https://eth.example.com/v2/key-from-file"), 0600))
+
+	configFile := filepath.Join(dir, "gas-oracle.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+loop_internal: 5s
+server:
+  host: ${TEST_GAS_ORACLE_HOST}
+  port: 8081
+  tls:
+    cert_file: /etc/tls/server.crt
+rpcs:
+  - rpc_url_file: `+apiKeyFile+`
+    chain_id: 1
+master_db:
+  db_host: 127.0.0.1
+  db_password_file: `+passwordFile+`
+`), 0600))
+
+	t.Setenv("TEST_GAS_ORACLE_HOST", "0.0.0.0")
+	t.Setenv("GAS_ORACLE_SERVER_PORT", "9090")
+	t.Setenv("GAS_ORACLE_LOOP_INTERNAL", "10s")
+	t.Setenv("GAS_ORACLE_RPCS_1_RPC_URL", "https://op.example.com")
+	t.Setenv("GAS_ORACLE_RPCS_1_CHAIN_ID", "10")
+
+	cfg, err := New(configFile)
+	require.NoError(t, err)
+
+	require.Equal(t, "0.0.0.0", cfg.Server.Host)
+	require.Equal(t, 9090, cfg.Server.Port)
+	require.Equal(t, "/etc/tls/server.crt", cfg.Server.TLS.CertFile)
+	require.Equal(t, 10*time.Second, cfg.LoopInternal)
+	require.Equal(t, "s3cret", cfg.MasterDb.DbPassword)
+	require.Len(t, cfg.RPCs, 2)
+	require.Equal(t, "https://eth.example.com/v2/key-from-file", cfg.RPCs[0].RpcUrl)
+	require.Equal(t, uint64(1), cfg.RPCs[0].ChainId)
+	require.Equal(t, "https://op.example.com", cfg.RPCs[1].RpcUrl)
+	require.Equal(t, uint64(10), cfg.RPCs[1].ChainId)
+}
+
+func TestNewWithUnsetEnvReference(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "gas-oracle.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("skyeye_url: ${TEST_GAS_ORACLE_UNSET}\n"), 0600))
+
+	_, err := New(configFile)
+	require.ErrorContains(t, err, "TEST_GAS_ORACLE_UNSET")
+}
+
+func TestNewExpandsEnvValuesVerbatim(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "gas-oracle.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+# set ${TEST_GAS_ORACLE_COMMENTED} to override the host
+loop_internal: ${TEST_GAS_ORACLE_LOOP}
+server:
+  host: ${TEST_GAS_ORACLE_HOST}
+  port: ${TEST_GAS_ORACLE_PORT}
+master_db:
+  db_user: "${TEST_GAS_ORACLE_USER}"
+  db_password: ${TEST_GAS_ORACLE_PASSWORD}
+  db_name: ${TEST_GAS_ORACLE_NAME}
+  db_host: ${TEST_GAS_ORACLE_DB_HOST_NUMBER}
+skyeye_url: ${TEST_GAS_ORACLE_SKYEYE}/api
+`), 0600))
+
+	t.Setenv("TEST_GAS_ORACLE_LOOP", "5s")
+	t.Setenv("TEST_GAS_ORACLE_HOST", "*anchor")
+	t.Setenv("TEST_GAS_ORACLE_PORT", "8081")
+	t.Setenv("TEST_GAS_ORACLE_USER", "!admin")
+	t.Setenv("TEST_GAS_ORACLE_PASSWORD", "p #w: {x}\ninjected: true")
+	t.Setenv("TEST_GAS_ORACLE_NAME", "0123")
+	t.Setenv("TEST_GAS_ORACLE_DB_HOST_NUMBER", "123456")
+	t.Setenv("TEST_GAS_ORACLE_SKYEYE", "http://skyeye")
+
+	cfg, err := New(configFile)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, cfg.LoopInternal)
+	require.Equal(t, "*anchor", cfg.Server.Host)
+	require.Equal(t, 8081, cfg.Server.Port)
+	require.Equal(t, "!admin", cfg.MasterDb.DbUser)
+	require.Equal(t, "p #w: {x}\ninjected: true", cfg.MasterDb.DbPassword)
+	require.Equal(t, "0123", cfg.MasterDb.DbName)
+	require.Equal(t, "123456", cfg.MasterDb.DbHost)
+	require.Equal(t, "http://skyeye/api", cfg.SkyeyeUrl)
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl: "http://skyeye",
+		Symbols:   []Symbols{{Name: "eth", Decimal: 18}},
+		RPCs: []*RPC{
+			{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH"},
+			{RpcUrl: "http://eth2", ChainId: 1, NativeToken: "ETH"},
+			{RpcUrl: "http://cp", ChainId: 86606, NativeToken: "CP"},
+		},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "loop_internal must be greater than zero")
+	require.ErrorContains(t, err, "back_offset must be greater than zero")
+	require.ErrorContains(t, err, "rpcs[1]: chain_id 1 already used by rpcs[0]")
+	require.ErrorContains(t, err, `rpcs[2]: native_token CP has no matching entry "cp" in symbols`)
+
+	cfg.LoopInternal = 5 * time.Second
+	cfg.BackOffset = 2
+	cfg.RPCs[1].ChainId = 10
+	cfg.Symbols = append(cfg.Symbols, Symbols{Name: "cp", Decimal: 18})
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateSymbolMapping(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl:    "http://skyeye",
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		Symbols: []Symbols{
+			{Name: "eth", Decimal: 18, Aliases: []string{"WETH", ""}, Addresses: []TokenAddress{
+				{ChainId: 1, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+				{ChainId: 10, Address: "not-an-address"},
+			}},
+			{Name: "pol", Decimal: 18, Aliases: []string{"weth", "matic"}, Addresses: []TokenAddress{
+				{ChainId: 1, Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"},
+			}},
+			{Name: "Matic", Decimal: 18},
+		},
+		RPCs:     []*RPC{{RpcUrl: "http://polygon", ChainId: 137, NativeToken: "MATIC"}},
+		DexPools: []DexPool{{ChainId: 137, Address: "0x45dDa9cb7c25131DF268515131f647d726f50608", Token0: "eth"}},
+	}
+	err := cfg.Validate()
+	require.ErrorContains(t, err, `symbols[0]: empty alias`)
+	require.ErrorContains(t, err, `symbols[0].addresses[1]: address "not-an-address" is not a hex address`)
+	require.ErrorContains(t, err, `symbols[1]: alias "weth" already names "eth"`)
+	require.ErrorContains(t, err, `symbols[1].addresses[0]: address 0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2 on chain 1 already belongs to "eth"`)
+	require.ErrorContains(t, err, `dex_pools[0]: token0 and token1 must name two different tokens or both be empty`)
+	require.ErrorContains(t, err, `symbols[2]: name "Matic" already names "pol"`)
+	require.NotContains(t, err.Error(), "native_token")
+
+	cfg.Symbols[0].Aliases = []string{"WETH"}
+	cfg.Symbols[0].Addresses = cfg.Symbols[0].Addresses[:1]
+	cfg.Symbols[1].Aliases = []string{"matic"}
+	cfg.Symbols[1].Addresses = []TokenAddress{{ChainId: 137, Address: "0x0000000000000000000000000000000000001010"}}
+	cfg.Symbols = cfg.Symbols[:2]
+	cfg.DexPools[0].Token0 = ""
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateQuoteCurrencies(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl:       "http://skyeye",
+		LoopInternal:    5 * time.Second,
+		BackOffset:      2,
+		QuoteCurrencies: []string{"USD", "eur", "USD"},
+	}
+	err := cfg.Validate()
+	require.ErrorContains(t, err, `quote_currencies[1]: "eur" must be an upper case currency code`)
+	require.ErrorContains(t, err, `quote_currencies[2]: duplicate currency "USD"`)
+
+	cfg.QuoteCurrencies = nil
+	require.NoError(t, cfg.Validate())
+	require.Equal(t, []string{DefaultQuoteCurrency}, cfg.QuoteCurrencyList())
+}
+
+func TestValidateDexPools(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl:    "http://skyeye",
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		Symbols:      []Symbols{{Name: "eth", Decimal: 18}},
+		RPCs:         []*RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH"}},
+		DexPools: []DexPool{
+			{ChainId: 10, Address: "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc", Token0: "USDC", Token1: "ETH"},
+			{ChainId: 1, Address: "pool", Token0: "ETH", Token1: "eth"},
+			{ChainId: 1, Address: "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", Token0: "USDC", Token1: "ETH", TwapWindow: time.Minute},
+			{ChainId: 1, Address: "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", Version: "v4", Token0: "USDC", Token1: "ETH"},
+		},
+	}
+	err := cfg.Validate()
+	require.ErrorContains(t, err, "dex_pools[0]: chain_id 10 is not one of the rpcs")
+	require.ErrorContains(t, err, `dex_pools[1]: address "pool" is not a hex address`)
+	require.ErrorContains(t, err, "dex_pools[1]: token0 and token1 must name two different tokens")
+	require.ErrorContains(t, err, "dex_pools[2]: twap_window needs a v3 pool")
+	require.ErrorContains(t, err, `dex_pools[3]: unknown version "v4", want v2 or v3`)
+
+	cfg.DexPools = cfg.DexPools[2:3]
+	cfg.DexPools[0].Version = "v3"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateChainlinkFeeds(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl:    "http://skyeye",
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		Symbols:      []Symbols{{Name: "eth", Decimal: 18}},
+		RPCs:         []*RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH"}},
+		ChainlinkFeeds: []ChainlinkFeed{
+			{ChainId: 10, Address: "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419", Base: "eth", Quote: "USD"},
+			{ChainId: 1, Address: "feed", Base: "eth", Quote: "ETH", MaxAge: -time.Second},
+		},
+	}
+	err := cfg.Validate()
+	require.ErrorContains(t, err, "chainlink_feeds[0]: chain_id 10 is not one of the rpcs")
+	require.ErrorContains(t, err, `chainlink_feeds[1]: address "feed" is not a hex address`)
+	require.ErrorContains(t, err, "chainlink_feeds[1]: base and quote must name two different tokens")
+	require.ErrorContains(t, err, "chainlink_feeds[1]: max_age must not be negative")
+
+	cfg.ChainlinkFeeds = cfg.ChainlinkFeeds[:1]
+	cfg.ChainlinkFeeds[0].ChainId = 1
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidatePriceGuard(t *testing.T) {
+	cfg := &Config{
+		SkyeyeUrl:    "http://skyeye",
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		PriceGuard:   PriceGuard{MaxDeviation: -1, Window: -time.Minute, Confirmations: -1},
+	}
+	cfg.PriceLoopInterval = -time.Second
+	cfg.PriceFetchConcurrency = -1
+	cfg.PriceRequestTimeout = -time.Second
+	err := cfg.Validate()
+	require.ErrorContains(t, err, "price_loop_interval must not be negative")
+	require.ErrorContains(t, err, "price_fetch_concurrency must not be negative")
+	require.ErrorContains(t, err, "price_request_timeout must not be negative")
+	require.ErrorContains(t, err, "price_guard: max_deviation must not be negative")
+	require.ErrorContains(t, err, "price_guard: window must not be negative")
+	require.ErrorContains(t, err, "price_guard: confirmations must not be negative")
+
+	cfg.PriceGuard = PriceGuard{MaxDeviation: 20, Window: time.Minute}
+	cfg.PriceLoopInterval = 0
+	cfg.PriceFetchConcurrency = 0
+	cfg.PriceRequestTimeout = 0
+	require.NoError(t, cfg.Validate())
+}
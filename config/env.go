@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix prefixes the environment variables overriding config fields,
+// e.g. GAS_ORACLE_MASTER_DB_DB_PASSWORD or GAS_ORACLE_RPCS_0_RPC_URL.
+const EnvPrefix = "GAS_ORACLE"
+
+// fileSuffix marks a variant whose value is the path of a file holding the
+// real value, e.g. db_password_file in yaml or GAS_ORACLE_MASTER_DB_DB_PASSWORD_FILE.
+const fileSuffix = "_file"
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references in the string values of the yaml
+// document with the value of the environment variable. The document is parsed
+// first, so values are never read as yaml and references in comments are ignored.
+func expandEnv(data []byte) ([]byte, error) {
+	var tree interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	missing := make(map[string]bool)
+	tree = expandEnvNode(tree, missing)
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("config references unset environment variables: %s", strings.Join(names, ", "))
+	}
+	return yaml.Marshal(tree)
+}
+
+func expandEnvNode(node interface{}, missing map[string]bool) interface{} {
+	switch node := node.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range node {
+			node[key] = expandEnvNode(value, missing)
+		}
+	case []interface{}:
+		for i, value := range node {
+			node[i] = expandEnvNode(value, missing)
+		}
+	case string:
+		if !envPattern.MatchString(node) {
+			return node
+		}
+		expanded := envPattern.ReplaceAllStringFunc(node, func(ref string) string {
+			name := envPattern.FindStringSubmatch(ref)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing[name] = true
+			}
+			return value
+		})
+		return envScalar(expanded)
+	}
+	return node
+}
+
+// envScalar keeps an expanded value a string unless it is a number or a
+// boolean written exactly as yaml writes it, so `port: ${PORT}` still fills an
+// int while a password like 0123 or `a #b` is kept verbatim.
+func envScalar(value string) interface{} {
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(value), &scalar); err != nil {
+		return value
+	}
+	switch scalar.(type) {
+	case int, int64, uint64, float64, bool:
+		if out, err := yaml.Marshal(scalar); err == nil && strings.TrimSpace(string(out)) == value {
+			return scalar
+		}
+	}
+	return value
+}
+
+// applyFileRefs resolves `<field>_file` yaml keys, reading the field value
+// from the referenced file. Fields whose own name ends in _file, such as
+// tls.cert_file, are left untouched.
+func applyFileRefs(v reflect.Value, node interface{}) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return applyFileRefs(v.Elem(), node)
+	case reflect.Slice:
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			if err := applyFileRefs(v.Index(i), items[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		fields, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		tags := make(map[string]bool)
+		for i := 0; i < v.NumField(); i++ {
+			tags[yamlTag(v.Type().Field(i))] = true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			tag := yamlTag(v.Type().Field(i))
+			if tag == "" {
+				continue
+			}
+			if path, ok := fields[tag+fileSuffix].(string); ok && !tags[tag+fileSuffix] {
+				value, err := readSecretFile(path)
+				if err != nil {
+					return fmt.Errorf("%s%s: %w", tag, fileSuffix, err)
+				}
+				if err := setFromString(v.Field(i), value); err != nil {
+					return fmt.Errorf("%s%s: %w", tag, fileSuffix, err)
+				}
+				continue
+			}
+			if err := applyFileRefs(v.Field(i), fields[tag]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyEnvOverrides overrides config fields from the environment. Slices are
+// addressed by index and grow when an index past their end is set.
+func applyEnvOverrides(v reflect.Value, path string, env map[string]string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if !hasEnvPrefix(env, path+"_") {
+				return nil
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return applyEnvOverrides(v.Elem(), path, env)
+	case reflect.Struct:
+		if isScalar(v.Type()) {
+			return applyEnvValue(v, path, env)
+		}
+		for i := 0; i < v.NumField(); i++ {
+			tag := yamlTag(v.Type().Field(i))
+			if tag == "" {
+				continue
+			}
+			if err := applyEnvOverrides(v.Field(i), path+"_"+strings.ToUpper(tag), env); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; ; i++ {
+			itemPath := path + "_" + strconv.Itoa(i)
+			if i >= v.Len() {
+				if !hasEnvPrefix(env, itemPath+"_") {
+					break
+				}
+				v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+			}
+			if err := applyEnvOverrides(v.Index(i), itemPath, env); err != nil {
+				return err
+			}
+		}
+	default:
+		return applyEnvValue(v, path, env)
+	}
+	return nil
+}
+
+func applyEnvValue(v reflect.Value, path string, env map[string]string) error {
+	if value, ok := env[path]; ok {
+		if err := setFromString(v, value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+	if file, ok := env[path+strings.ToUpper(fileSuffix)]; ok {
+		value, err := readSecretFile(file)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", path, strings.ToUpper(fileSuffix), err)
+		}
+		if err := setFromString(v, value); err != nil {
+			return fmt.Errorf("%s%s: %w", path, strings.ToUpper(fileSuffix), err)
+		}
+	}
+	return nil
+}
+
+func setFromString(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+	// let yaml parse everything else so durations, times and numbers behave
+	// exactly as they do in the config file
+	return yaml.Unmarshal([]byte(value), v.Addr().Interface())
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func yamlTag(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if tag == "-" {
+		return ""
+	}
+	return tag
+}
+
+// isScalar reports struct types that are set as a single value, like time.Time.
+func isScalar(t reflect.Type) bool {
+	return t.PkgPath() == "time"
+}
+
+func hasEnvPrefix(env map[string]string, prefix string) bool {
+	for key := range env {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func environ(prefix string) map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(key, prefix+"_") {
+			env[key] = value
+		}
+	}
+	return env
+}
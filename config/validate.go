@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Validate reports every problem in the config at once, so a bad deployment
+// fails at startup instead of somewhere inside a running loop.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.LoopInternal <= 0 {
+		fail("loop_internal must be greater than zero")
+	}
+	if c.BackOffset == 0 {
+		fail("back_offset must be greater than zero")
+	}
+	if c.SkyeyeUrl == "" {
+		fail("skyeye_url is required")
+	}
+
+	symbols := make(map[string]bool, len(c.Symbols))
+	for i, symbol := range c.Symbols {
+		switch {
+		case symbol.Name == "":
+			fail("symbols[%d]: name is required", i)
+		case symbols[symbol.Name]:
+			fail("symbols[%d]: duplicate symbol %q", i, symbol.Name)
+		}
+		symbols[symbol.Name] = true
+	}
+	// names resolves every name and alias case-insensitively, so none may
+	// stand for two symbols
+	names := make(map[string]string, len(c.Symbols))
+	addresses := make(map[TokenAddress]string)
+	for i, symbol := range c.Symbols {
+		if other, ok := names[strings.ToLower(symbol.Name)]; ok && symbol.Name != "" && other != symbol.Name {
+			fail("symbols[%d]: name %q already names %q", i, symbol.Name, other)
+		} else if symbol.Name != "" {
+			names[strings.ToLower(symbol.Name)] = symbol.Name
+		}
+		for _, alias := range symbol.Aliases {
+			if alias == "" {
+				fail("symbols[%d]: empty alias", i)
+			} else if other, ok := names[strings.ToLower(alias)]; ok && other != symbol.Name {
+				fail("symbols[%d]: alias %q already names %q", i, alias, other)
+			} else {
+				names[strings.ToLower(alias)] = symbol.Name
+			}
+		}
+		for j, address := range symbol.Addresses {
+			key := TokenAddress{ChainId: address.ChainId, Address: strings.ToLower(address.Address)}
+			switch {
+			case address.ChainId == 0:
+				fail("symbols[%d].addresses[%d]: chain_id is required", i, j)
+			case !common.IsHexAddress(address.Address):
+				fail("symbols[%d].addresses[%d]: address %q is not a hex address", i, j, address.Address)
+			case addresses[key] != "":
+				fail("symbols[%d].addresses[%d]: address %s on chain %d already belongs to %q", i, j, address.Address, address.ChainId, addresses[key])
+			default:
+				addresses[key] = symbol.Name
+			}
+		}
+	}
+
+	currencies := make(map[string]bool, len(c.QuoteCurrencies))
+	for i, currency := range c.QuoteCurrencies {
+		switch {
+		case currency == "" || currency != strings.ToUpper(currency):
+			fail("quote_currencies[%d]: %q must be an upper case currency code such as USD", i, currency)
+		case currencies[currency]:
+			fail("quote_currencies[%d]: duplicate currency %q", i, currency)
+		}
+		currencies[currency] = true
+	}
+
+	chainIds := make(map[uint64]int, len(c.RPCs))
+	for i, rpc := range c.RPCs {
+		if rpc == nil {
+			fail("rpcs[%d]: empty entry", i)
+			continue
+		}
+		if rpc.RpcUrl == "" {
+			fail("rpcs[%d]: rpc_url is required", i)
+		}
+		if rpc.ChainId == 0 {
+			fail("rpcs[%d]: chain_id is required", i)
+		} else if first, ok := chainIds[rpc.ChainId]; ok {
+			fail("rpcs[%d]: chain_id %d already used by rpcs[%d]", i, rpc.ChainId, first)
+		} else {
+			chainIds[rpc.ChainId] = i
+		}
+		if rpc.NativeToken == "" {
+			fail("rpcs[%d]: native_token is required", i)
+		} else if _, ok := names[strings.ToLower(rpc.NativeToken)]; !ok {
+			// the native token price is looked up by the symbol it resolves to
+			fail("rpcs[%d]: native_token %s has no matching entry %q in symbols", i, rpc.NativeToken, strings.ToLower(rpc.NativeToken))
+		}
+		if rpc.LoopInternal < 0 {
+			fail("rpcs[%d]: loop_internal must not be negative", i)
+		}
+	}
+
+	switch c.MasterDb.Driver {
+	case "", "postgres", "sqlite", "memory":
+	default:
+		fail("master_db: unknown driver %q, want postgres, sqlite or memory", c.MasterDb.Driver)
+	}
+	if c.MasterDb.Driver == "sqlite" && c.MasterDb.DbName == "" {
+		fail("master_db: db_name must name the sqlite database file")
+	}
+
+	for i, pool := range c.DexPools {
+		if _, ok := chainIds[pool.ChainId]; !ok {
+			fail("dex_pools[%d]: chain_id %d is not one of the rpcs", i, pool.ChainId)
+		}
+		if !common.IsHexAddress(pool.Address) {
+			fail("dex_pools[%d]: address %q is not a hex address", i, pool.Address)
+		}
+		// a pool without token names is matched to symbols by its token addresses
+		if (pool.Token0 == "") != (pool.Token1 == "") || (pool.Token0 != "" && strings.EqualFold(pool.Token0, pool.Token1)) {
+			fail("dex_pools[%d]: token0 and token1 must name two different tokens or both be empty", i)
+		}
+		switch pool.Version {
+		case "", "v2":
+			if pool.TwapWindow != 0 {
+				fail("dex_pools[%d]: twap_window needs a v3 pool", i)
+			}
+		case "v3":
+			if pool.TwapWindow < 0 || pool.TwapWindow%time.Second != 0 {
+				fail("dex_pools[%d]: twap_window must be a positive number of seconds", i)
+			}
+		default:
+			fail("dex_pools[%d]: unknown version %q, want v2 or v3", i, pool.Version)
+		}
+	}
+	for i, feed := range c.ChainlinkFeeds {
+		if _, ok := chainIds[feed.ChainId]; !ok {
+			fail("chainlink_feeds[%d]: chain_id %d is not one of the rpcs", i, feed.ChainId)
+		}
+		if !common.IsHexAddress(feed.Address) {
+			fail("chainlink_feeds[%d]: address %q is not a hex address", i, feed.Address)
+		}
+		if feed.Base == "" || feed.Quote == "" || strings.EqualFold(feed.Base, feed.Quote) {
+			fail("chainlink_feeds[%d]: base and quote must name two different tokens", i)
+		}
+		if feed.MaxAge < 0 {
+			fail("chainlink_feeds[%d]: max_age must not be negative", i)
+		}
+	}
+
+	if c.PriceLoopInterval < 0 {
+		fail("price_loop_interval must not be negative")
+	}
+	if c.PriceFetchConcurrency < 0 {
+		fail("price_fetch_concurrency must not be negative")
+	}
+	if c.PriceRequestTimeout < 0 {
+		fail("price_request_timeout must not be negative")
+	}
+	if c.PriceGuard.MaxDeviation < 0 {
+		fail("price_guard: max_deviation must not be negative")
+	}
+	if c.PriceGuard.Window < 0 {
+		fail("price_guard: window must not be negative")
+	}
+	if c.PriceGuard.Confirmations < 0 {
+		fail("price_guard: confirmations must not be negative")
+	}
+
+	if c.Server.Port == c.Server.HttpPort && c.Server.Port != 0 {
+		fail("server: port and http_port must differ")
+	}
+	if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+		fail("server.tls: cert_file and key_file must be set together")
+	}
+	if c.Server.TLS.CertFile == "" && (c.Server.TLS.ClientCAFile != "" || c.Server.TLS.RequireClientCert) {
+		fail("server.tls: client certificates require cert_file and key_file")
+	}
+	if c.Server.TLS.RequireClientCert && c.Server.TLS.ClientCAFile == "" {
+		fail("server.tls: require_client_cert needs client_ca_file")
+	}
+
+	tokens := make(map[string]bool, len(c.Auth.Consumers))
+	for i, consumer := range c.Auth.Consumers {
+		if consumer.Name == "" {
+			fail("auth.consumers[%d]: name is required", i)
+		}
+		switch {
+		case consumer.Token == "":
+			fail("auth.consumers[%d]: token is required", i)
+		case tokens[consumer.Token]:
+			fail("auth.consumers[%d]: duplicate token", i)
+		}
+		tokens[consumer.Token] = true
+	}
+
+	for i, token := range c.Admin.Tokens {
+		if token == "" {
+			fail("admin.tokens[%d]: token must not be empty", i)
+		}
+	}
+
+	if c.LeaderElection.Enable {
+		if c.LeaderElection.LeaseTTL <= 0 || c.LeaderElection.RenewInterval <= 0 {
+			fail("leader_election: lease_ttl and renew_interval must be greater than zero")
+		} else if c.LeaderElection.RenewInterval*2 > c.LeaderElection.LeaseTTL {
+			fail("leader_election: lease_ttl must be at least twice renew_interval")
+		}
+	}
+
+	if c.RateLimit.Rate < 0 || c.RateLimit.Burst < 0 {
+		fail("rate_limit: rate and burst must not be negative")
+	}
+	for i, consumer := range c.RateLimit.Consumers {
+		if consumer.Name == "" {
+			fail("rate_limit.consumers[%d]: name is required", i)
+		}
+		if consumer.Rate < 0 || consumer.Burst < 0 {
+			fail("rate_limit.consumers[%d]: rate and burst must not be negative", i)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type ApiConsumer struct {
+	GUID      uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	Enabled   bool      `json:"enabled"`
+	ExpireAt  uint64    `json:"expire_at"`
+	Timestamp uint64    `json:"timestamp"`
+}
+
+func (ApiConsumer) TableName() string {
+	return "api_consumer"
+}
+
+type ApiConsumerAudit struct {
+	GUID         uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	ConsumerName string    `json:"consumer_name"`
+	Method       string    `json:"method"`
+	PeerAddr     string    `json:"peer_addr"`
+	Timestamp    uint64    `json:"timestamp"`
+}
+
+func (ApiConsumerAudit) TableName() string {
+	return "api_consumer_audit"
+}
+
+type apiConsumerDB struct {
+	gorm *gorm.DB
+}
+
+type ApiConsumerDB interface {
+	ApiConsumerView
+	StoreApiConsumerAudit(audit *ApiConsumerAudit) error
+}
+
+type ApiConsumerView interface {
+	QueryApiConsumer(token string) (*ApiConsumer, error)
+}
+
+func NewApiConsumerDB(db *gorm.DB) ApiConsumerDB {
+	return &apiConsumerDB{gorm: db}
+}
+
+// QueryApiConsumer returns the consumer owning token, or nil when no such consumer exists.
+func (db *apiConsumerDB) QueryApiConsumer(token string) (*ApiConsumer, error) {
+	var apiConsumer ApiConsumer
+	err := db.gorm.Table("api_consumer").Where("token = ?", token).Take(&apiConsumer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error("get api consumer fail", "err", err)
+		return nil, err
+	}
+	return &apiConsumer, nil
+}
+
+func (db *apiConsumerDB) StoreApiConsumerAudit(audit *ApiConsumerAudit) error {
+	result := db.gorm.Table("api_consumer_audit").Omit("guid").Create(audit)
+	return result.Error
+}
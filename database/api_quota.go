@@ -0,0 +1,63 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type ApiQuota struct {
+	GUID         uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	QuotaKey     string    `json:"quota_key"`
+	Day          string    `json:"day"`
+	RequestCount uint64    `json:"request_count"`
+	Timestamp    uint64    `json:"timestamp"`
+}
+
+func (ApiQuota) TableName() string {
+	return "api_quota"
+}
+
+type apiQuotaDB struct {
+	gorm *gorm.DB
+}
+
+type ApiQuotaDB interface {
+	ApiQuotaView
+	IncreaseApiQuota(quotaKey string, day string, count uint64) (uint64, error)
+}
+
+type ApiQuotaView interface {
+	QueryApiQuota(quotaKey string, day string) (*ApiQuota, error)
+}
+
+func NewApiQuotaDB(db *gorm.DB) ApiQuotaDB {
+	return &apiQuotaDB{gorm: db}
+}
+
+// IncreaseApiQuota atomically counts count more requests for quotaKey on day
+// and returns the updated count, the stored one when count is zero.
+func (db *apiQuotaDB) IncreaseApiQuota(quotaKey string, day string, count uint64) (uint64, error) {
+	var requestCount uint64
+	err := db.gorm.Raw(`INSERT INTO api_quota (quota_key, day, request_count, timestamp) VALUES (?, ?, ?, ?)
+		ON CONFLICT (quota_key, day) DO UPDATE SET request_count = api_quota.request_count + EXCLUDED.request_count, timestamp = EXCLUDED.timestamp
+		RETURNING request_count`, quotaKey, day, count, time.Now().Unix()).Scan(&requestCount).Error
+	if err != nil {
+		log.Error("increase api quota fail", "quotaKey", quotaKey, "err", err)
+		return 0, err
+	}
+	return requestCount, nil
+}
+
+func (db *apiQuotaDB) QueryApiQuota(quotaKey string, day string) (*ApiQuota, error) {
+	var apiQuota ApiQuota
+	err := db.gorm.Table("api_quota").Where("quota_key = ? AND day = ?", quotaKey, day).Take(&apiQuota).Error
+	if err != nil {
+		log.Error("get api quota fail", "err", err)
+		return nil, err
+	}
+	return &apiQuota, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type ChainConfig struct {
+	GUID         uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	ChainId      *big.Int  `json:"chain_id" gorm:"serializer:u256"`
+	ChainName    string    `json:"chain_name"`
+	RpcUrls      []string  `json:"rpc_urls" gorm:"serializer:json"`
+	NativeToken  string    `json:"native_token"`
+	Decimal      uint8     `json:"decimal"`
+	Enabled      bool      `json:"enabled"`
+	BackOffset   uint64    `json:"back_offset"`
+	LoopInterval uint64    `json:"loop_interval"`
+	Timestamp    uint64    `json:"timestamp"`
+}
+
+func (ChainConfig) TableName() string {
+	return "chain_config"
+}
+
+type chainConfigDB struct {
+	gorm *gorm.DB
+}
+
+type ChainConfigDB interface {
+	ChainConfigView
+	StoreOrUpdateChainConfig(chainConfig *ChainConfig) error
+	DeleteChainConfig(chainId *big.Int) (bool, error)
+}
+
+type ChainConfigView interface {
+	QueryChainConfigList() ([]ChainConfig, error)
+}
+
+func NewChainConfigDB(db *gorm.DB) ChainConfigDB {
+	return &chainConfigDB{gorm: db}
+}
+
+func (db *chainConfigDB) StoreOrUpdateChainConfig(chainConfig *ChainConfig) error {
+	result := db.gorm.Table("chain_config").Omit("guid").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"chain_name", "rpc_urls", "native_token", "decimal", "enabled", "back_offset", "loop_interval", "timestamp"}),
+	}).Create(chainConfig)
+	if result.Error != nil {
+		log.Error("store or update chain config fail", "err", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteChainConfig removes the chain and reports whether it existed.
+func (db *chainConfigDB) DeleteChainConfig(chainId *big.Int) (bool, error) {
+	result := db.gorm.Table("chain_config").Where("chain_id = ?", chainId.String()).Delete(&ChainConfig{})
+	if result.Error != nil {
+		log.Error("delete chain config fail", "err", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (db *chainConfigDB) QueryChainConfigList() ([]ChainConfig, error) {
+	var chainConfigList []ChainConfig
+	err := db.gorm.Table("chain_config").Order("chain_id asc").Find(&chainConfigList).Error
+	if err != nil {
+		log.Error("get chain config list fail", "err", err)
+		return nil, err
+	}
+	return chainConfigList, nil
+}
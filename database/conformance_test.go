@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/cpchain-network/gas-oracle/common/bigint"
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+// testConformance runs the behaviour every storage driver must share against
+// the empty database newDB returns.
+func testConformance(t *testing.T, newDB func(t *testing.T) *DB) {
+	t.Run("GasFeeUpsert", func(t *testing.T) {
+		db := newDB(t)
+		_, err := db.GasFee.QueryGasFees("1")
+		require.True(t, errors.Is(err, gorm.ErrRecordNotFound), "want gorm.ErrRecordNotFound, got %v", err)
+
+		require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(1), Timestamp: 1}))
+		require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(2), TokenName: "CP", Decimal: 18, PredictFee: big.NewInt(5), Timestamp: 1}))
+		first, err := db.GasFee.QueryGasFees("10")
+		require.NoError(t, err)
+		require.Nil(t, first.BaseFee)
+
+		require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(2), Timestamp: 2, BaseFee: big.NewInt(7)}))
+		second, err := db.GasFee.QueryGasFees("10")
+		require.NoError(t, err)
+		require.Equal(t, first.GUID, second.GUID)
+		require.Equal(t, 0, big.NewInt(2).Cmp(second.PredictFee))
+		require.Equal(t, uint64(2), second.Timestamp)
+		require.Equal(t, 0, big.NewInt(7).Cmp(second.BaseFee))
+
+		gasFeeList, err := db.GasFee.QueryGasFeeList()
+		require.NoError(t, err)
+		require.Len(t, gasFeeList, 2)
+		require.Equal(t, int64(2), gasFeeList[0].ChainId.Int64(), "ordered by chain id")
+		require.Equal(t, int64(10), gasFeeList[1].ChainId.Int64())
+	})
+
+	t.Run("TokenPriceUpsert", func(t *testing.T) {
+		db := newDB(t)
+		_, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+		require.True(t, errors.Is(err, gorm.ErrRecordNotFound), "want gorm.ErrRecordNotFound, got %v", err)
+
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "USD", Decimal: 18, MarketPrice: big.NewRat(3, 2), Timestamp: 1}))
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "ether", TokenSymbol: "eth", SkeyeSymbol: "weth", QuoteCurrency: "USD", Decimal: 8, MarketPrice: big.NewRat(5, 2), Timestamp: 2}))
+		tokenPrice, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+		require.NoError(t, err)
+		require.Equal(t, "2.5", tokenPrice.MarketPriceString())
+		require.Equal(t, uint64(2), tokenPrice.Timestamp)
+		require.Equal(t, "eth", tokenPrice.TokenName, "only the price and timestamp are updated")
+		require.Equal(t, uint8(18), tokenPrice.Decimal)
+		require.Equal(t, "weth", tokenPrice.SkeyeSymbol)
+
+		// cheap tokens keep every digit up to the 18 digit scale
+		cheap, err := bigint.ParseDecimal("0.000000012345678912")
+		require.NoError(t, err)
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "pepe", TokenSymbol: "pepe", QuoteCurrency: "USD", Decimal: 18, MarketPrice: cheap, Timestamp: 1}))
+		tokenPrice, err = db.TokenPrice.QueryTokenPrices("pepe", "USD")
+		require.NoError(t, err)
+		require.Equal(t, "0.000000012345678912", tokenPrice.MarketPriceString())
+
+		// each quote currency keeps its own row
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "EUR", Decimal: 18, MarketPrice: big.NewRat(9, 4), Timestamp: 3}))
+		eur, err := db.TokenPrice.QueryTokenPrices("eth", "EUR")
+		require.NoError(t, err)
+		require.Equal(t, "2.25", eur.MarketPriceString())
+		require.Equal(t, "EUR", eur.QuoteCurrency)
+		usd, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+		require.NoError(t, err)
+		require.Equal(t, "2.5", usd.MarketPriceString())
+		_, err = db.TokenPrice.QueryTokenPrices("eth", "CNY")
+		require.True(t, errors.Is(err, gorm.ErrRecordNotFound), "want gorm.ErrRecordNotFound, got %v", err)
+
+		// a derived price records how it was computed
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "CNY", Decimal: 18, MarketPrice: big.NewRat(18, 1), DerivationPath: "eth/USD@skyeye,USD/CNY@skyeye", Confidence: 0.95, Timestamp: 4}))
+		cny, err := db.TokenPrice.QueryTokenPrices("eth", "CNY")
+		require.NoError(t, err)
+		require.Equal(t, "eth/USD@skyeye,USD/CNY@skyeye", cny.DerivationPath)
+		require.Equal(t, 0.95, cny.Confidence)
+
+		// freezing keeps the price, the next stored price resets the state
+		require.NoError(t, db.TokenPrice.UpdateTokenPriceState("eth", "CNY", PriceStateFrozen, "moved 40%"))
+		cny, err = db.TokenPrice.QueryTokenPrices("eth", "CNY")
+		require.NoError(t, err)
+		require.Equal(t, PriceStateFrozen, cny.PriceState)
+		require.Equal(t, "moved 40%", cny.PriceStateReason)
+		require.Equal(t, "18", cny.MarketPriceString())
+		require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "CNY", Decimal: 18, MarketPrice: big.NewRat(19, 1), PriceState: PriceStateOk, Confidence: 1, Timestamp: 5}))
+		cny, err = db.TokenPrice.QueryTokenPrices("eth", "CNY")
+		require.NoError(t, err)
+		require.Equal(t, PriceStateOk, cny.PriceState)
+		require.Empty(t, cny.PriceStateReason)
+		require.NoError(t, db.TokenPrice.UpdateTokenPriceState("btc", "CNY", PriceStateFrozen, "unknown pair"))
+	})
+
+	t.Run("GasFeeHistory", func(t *testing.T) {
+		db := newDB(t)
+		var histories []GasFeeHistory
+		for block := int64(100); block < 110; block++ {
+			histories = append(histories, GasFeeHistory{ChainId: big.NewInt(1), BlockNumber: big.NewInt(block), BaseFee: big.NewInt(block * 10), GasUsedRatio: "0.5", Timestamp: uint64(block)})
+		}
+		require.NoError(t, db.GasFeeHistory.StoreGasFeeHistories(histories))
+		// blocks already sampled are skipped
+		duplicate := histories[0]
+		duplicate.GasUsedRatio = "0.9"
+		require.NoError(t, db.GasFeeHistory.StoreGasFeeHistories([]GasFeeHistory{duplicate}))
+
+		latest, err := db.GasFeeHistory.QueryGasFeeHistories("1", nil, 3)
+		require.NoError(t, err)
+		require.Len(t, latest, 3)
+		require.Equal(t, int64(107), latest[0].BlockNumber.Int64(), "the newest blocks, oldest first")
+		require.Equal(t, int64(109), latest[2].BlockNumber.Int64())
+		require.Equal(t, 0, big.NewInt(1090).Cmp(latest[2].BaseFee))
+
+		require.NoError(t, db.GasFeeHistory.DeleteGasFeeHistoriesBefore("1", big.NewInt(105)))
+		remaining, err := db.GasFeeHistory.QueryGasFeeHistories("1", big.NewInt(106), 100)
+		require.NoError(t, err)
+		require.Len(t, remaining, 2)
+		require.Equal(t, int64(105), remaining[0].BlockNumber.Int64())
+
+		other, err := db.GasFeeHistory.QueryGasFeeHistories("10", nil, 100)
+		require.NoError(t, err)
+		require.Empty(t, other)
+	})
+
+	t.Run("ApiQuota", func(t *testing.T) {
+		db := newDB(t)
+		for i := uint64(1); i <= 3; i++ {
+			count, err := db.ApiQuota.IncreaseApiQuota("consumer:a", "2026-10-19", 1)
+			require.NoError(t, err)
+			require.Equal(t, i, count)
+		}
+		count, err := db.ApiQuota.IncreaseApiQuota("consumer:a", "2026-10-20", 1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), count)
+		count, err = db.ApiQuota.IncreaseApiQuota("consumer:a", "2026-10-20", 5)
+		require.NoError(t, err)
+		require.Equal(t, uint64(6), count)
+		count, err = db.ApiQuota.IncreaseApiQuota("consumer:a", "2026-10-21", 0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), count, "counting nothing reads the stored count")
+
+		apiQuota, err := db.ApiQuota.QueryApiQuota("consumer:a", "2026-10-19")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), apiQuota.RequestCount)
+
+		apiConsumer, err := db.ApiConsumer.QueryApiConsumer("unknown")
+		require.NoError(t, err)
+		require.Nil(t, apiConsumer)
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		db := newDB(t)
+		require.NoError(t, db.ChainConfig.StoreOrUpdateChainConfig(&ChainConfig{ChainId: big.NewInt(56), RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Enabled: true}))
+		require.NoError(t, db.ChainConfig.StoreOrUpdateChainConfig(&ChainConfig{ChainId: big.NewInt(1), RpcUrls: []string{"http://eth"}, NativeToken: "ETH"}))
+		require.NoError(t, db.ChainConfig.StoreOrUpdateChainConfig(&ChainConfig{ChainId: big.NewInt(1), RpcUrls: []string{"http://eth2", "http://eth3"}, NativeToken: "ETH", LoopInterval: 3}))
+		chainConfigList, err := db.ChainConfig.QueryChainConfigList()
+		require.NoError(t, err)
+		require.Len(t, chainConfigList, 2)
+		require.Equal(t, int64(1), chainConfigList[0].ChainId.Int64())
+		require.Equal(t, []string{"http://eth2", "http://eth3"}, chainConfigList[0].RpcUrls)
+		require.Equal(t, uint64(3), chainConfigList[0].LoopInterval)
+
+		deleted, err := db.ChainConfig.DeleteChainConfig(big.NewInt(56))
+		require.NoError(t, err)
+		require.True(t, deleted)
+		deleted, err = db.ChainConfig.DeleteChainConfig(big.NewInt(56))
+		require.NoError(t, err)
+		require.False(t, deleted)
+
+		require.NoError(t, db.TokenConfig.StoreOrUpdateTokenConfig(&TokenConfig{Symbol: "weth", SkeyeSymbol: "eth", Decimal: 18, Enabled: true}))
+		require.NoError(t, db.TokenConfig.StoreOrUpdateTokenConfig(&TokenConfig{Symbol: "usdt", Decimal: 6, Enabled: true}))
+		require.NoError(t, db.TokenConfig.StoreOrUpdateTokenConfig(&TokenConfig{Symbol: "usdt", Decimal: 6, Enabled: true,
+			Aliases: []string{"tether"}, Addresses: map[uint64]string{1: "0xdAC17F958D2ee523a2206206994597C13D831ec7"}}))
+		tokenConfigList, err := db.TokenConfig.QueryTokenConfigList()
+		require.NoError(t, err)
+		require.Equal(t, []string{"usdt", "weth"}, []string{tokenConfigList[0].Symbol, tokenConfigList[1].Symbol})
+		require.Equal(t, []string{"tether"}, tokenConfigList[0].Aliases)
+		require.Equal(t, map[uint64]string{1: "0xdAC17F958D2ee523a2206206994597C13D831ec7"}, tokenConfigList[0].Addresses)
+		require.Empty(t, tokenConfigList[1].Aliases)
+		deleted, err = db.TokenConfig.DeleteTokenConfig("usdt")
+		require.NoError(t, err)
+		require.True(t, deleted)
+	})
+
+	t.Run("IndexerLease", func(t *testing.T) {
+		db := newDB(t)
+		ok, err := db.IndexerLease.AcquireLease("chain:1", "a", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = db.IndexerLease.AcquireLease("chain:1", "b", time.Minute)
+		require.NoError(t, err)
+		require.False(t, ok, "a live lease is not taken over")
+		ok, err = db.IndexerLease.AcquireLease("chain:1", "a", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok, "the holder renews its lease")
+
+		count, err := db.IndexerLease.CountLiveLeases("chain:")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+
+		require.NoError(t, db.IndexerLease.ReleaseLease("chain:1", "b"))
+		require.NoError(t, db.IndexerLease.ReleaseLease("chain:1", "a"))
+		ok, err = db.IndexerLease.AcquireLease("chain:1", "b", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		db := newDB(t)
+		err := db.Transaction(func(tx *DB) error {
+			if err := tx.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(1), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(1)}); err != nil {
+				return err
+			}
+			return tx.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "USD", Decimal: 18, MarketPrice: big.NewRat(1, 1)})
+		})
+		require.NoError(t, err)
+		_, err = db.GasFee.QueryGasFees("1")
+		require.NoError(t, err)
+		_, err = db.TokenPrice.QueryTokenPrices("eth", "USD")
+		require.NoError(t, err)
+
+		failure := errors.New("round failed")
+		err = db.Transaction(func(tx *DB) error {
+			if err := tx.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(1), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(2)}); err != nil {
+				return err
+			}
+			if err := tx.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "btc", TokenSymbol: "btc", QuoteCurrency: "USD", Decimal: 8, MarketPrice: big.NewRat(1, 1)}); err != nil {
+				return err
+			}
+			return failure
+		})
+		require.ErrorIs(t, err, failure)
+		gasFee, err := db.GasFee.QueryGasFees("1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), gasFee.PredictFee.Int64(), "the update is rolled back")
+		_, err = db.TokenPrice.QueryTokenPrices("btc", "USD")
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound, "the insert is rolled back")
+	})
+}
+
+func TestMemoryConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) *DB {
+		return NewMemoryDB()
+	})
+}
+
+func TestPostgresConformance(t *testing.T) {
+	testConformance(t, testDB)
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) *DB {
+		db, err := NewSQLiteDB(context.Background(), filepath.Join(t.TempDir(), "gas-oracle.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, db.Close()) })
+		return db
+	})
+}
+
+func TestNewDBSelectsDriver(t *testing.T) {
+	db, err := NewDB(context.Background(), config.Database{Driver: DriverMemory})
+	require.NoError(t, err)
+	_, err = db.MigrateUp(nil)
+	require.ErrorContains(t, err, "postgres")
+	require.NoError(t, db.Close())
+
+	_, err = NewDB(context.Background(), config.Database{Driver: "mysql"})
+	require.ErrorContains(t, err, `unknown database driver "mysql"`)
+}
@@ -2,26 +2,55 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 
-	"github.com/pkg/errors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"path/filepath"
 
 	"github.com/cpchain-network/gas-oracle/config"
 	_ "github.com/cpchain-network/gas-oracle/database/utils/serializers"
 	"github.com/cpchain-network/gas-oracle/synchronizer/retry"
 )
 
+// Storage drivers selectable with the driver field of the database config.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 type DB struct {
-	gorm       *gorm.DB
-	GasFee     GasFeeDB
-	TokenPrice TokenPriceDB
+	// gorm is only set for postgres, the one driver migrations apply to
+	gorm          *gorm.DB
+	GasFee        GasFeeDB
+	GasFeeHistory GasFeeHistoryDB
+	TokenPrice    TokenPriceDB
+	ApiConsumer   ApiConsumerDB
+	ApiQuota      ApiQuotaDB
+	ChainConfig   ChainConfigDB
+	TokenConfig   TokenConfigDB
+	IndexerLease  IndexerLeaseDB
+
+	transaction func(fn func(db *DB) error) error
+	close       func() error
 }
 
+// NewDB opens the storage backend named by the driver of dbConfig.
 func NewDB(ctx context.Context, dbConfig config.Database) (*DB, error) {
+	switch dbConfig.Driver {
+	case "", DriverPostgres:
+		return newPostgresDB(ctx, dbConfig)
+	case DriverSQLite:
+		return NewSQLiteDB(ctx, dbConfig.DbName)
+	case DriverMemory:
+		return NewMemoryDB(), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", dbConfig.Driver)
+	}
+}
+
+func newPostgresDB(ctx context.Context, dbConfig config.Database) (*DB, error) {
 	dsn := fmt.Sprintf("host=%s dbname=%s sslmode=disable", dbConfig.DbHost, dbConfig.DbName)
 	if dbConfig.DbPort != 0 {
 		dsn += fmt.Sprintf(" port=%d", dbConfig.DbPort)
@@ -49,50 +78,54 @@ func NewDB(ctx context.Context, dbConfig config.Database) (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newGormDB(gorm), nil
+}
+
+func newGormDB(conn *gorm.DB) *DB {
 	db := &DB{
-		gorm:       gorm,
-		GasFee:     NewGasFeeDB(gorm),
-		TokenPrice: NewTokenPriceDB(gorm),
+		gorm:          conn,
+		GasFee:        NewGasFeeDB(conn),
+		GasFeeHistory: NewGasFeeHistoryDB(conn),
+		TokenPrice:    NewTokenPriceDB(conn),
+		ApiConsumer:   NewApiConsumerDB(conn),
+		ApiQuota:      NewApiQuotaDB(conn),
+		ChainConfig:   NewChainConfigDB(conn),
+		TokenConfig:   NewTokenConfigDB(conn),
+		IndexerLease:  NewIndexerLeaseDB(conn),
+	}
+	db.transaction = func(fn func(db *DB) error) error {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			return fn(newGormDB(tx))
+		})
+	}
+	db.close = func() error {
+		sql, err := conn.DB()
+		if err != nil {
+			return err
+		}
+		return sql.Close()
 	}
-	return db, nil
+	return db
 }
 
 func (db *DB) Transaction(fn func(db *DB) error) error {
-	return db.gorm.Transaction(func(gorm *gorm.DB) error {
-		txDB := &DB{
-			gorm:       gorm,
-			GasFee:     NewGasFeeDB(gorm),
-			TokenPrice: NewTokenPriceDB(gorm),
-		}
-		return fn(txDB)
-	})
+	if db.transaction == nil {
+		return fn(db)
+	}
+	return db.transaction(fn)
 }
 
 func (db *DB) Close() error {
-	sql, err := db.gorm.DB()
-	if err != nil {
-		return err
+	if db.close == nil {
+		return nil
 	}
-	return sql.Close()
+	return db.close()
 }
 
-func (db *DB) ExecuteSQLMigration(migrationsFolder string) error {
-	err := filepath.Walk(migrationsFolder, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("Failed to process migration file: %s", path))
-		}
-		if info.IsDir() {
-			return nil
-		}
-		fileContent, readErr := os.ReadFile(path)
-		if readErr != nil {
-			return errors.Wrap(readErr, fmt.Sprintf("Error reading SQL file: %s", path))
-		}
-		execErr := db.gorm.Exec(string(fileContent)).Error
-		if execErr != nil {
-			return errors.Wrap(execErr, fmt.Sprintf("Error executing SQL script: %s", path))
-		}
-		return nil
-	})
-	return err
+// requirePostgres guards the operations only the postgres driver supports.
+func (db *DB) requirePostgres() error {
+	if db.gorm == nil {
+		return errors.New("migrations only apply to the postgres driver, other drivers create their schema on open")
+	}
+	return nil
 }
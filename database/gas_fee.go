@@ -2,21 +2,24 @@ package database
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"math/big"
 
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 
 	"github.com/ethereum/go-ethereum/log"
 )
 
 type GasFee struct {
-	GUID       uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
-	ChainId    *big.Int  `json:"chain_id" gorm:"serializer:u256"`
-	TokenName  string    `json:"token_name"`
-	Decimal    uint8     `json:"decimal"`
-	PredictFee string    `json:"predict_fee"`
-	Timestamp  uint64    `json:"timestamp"`
+	GUID        uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	ChainId     *big.Int  `json:"chain_id" gorm:"serializer:u256"`
+	TokenName   string    `json:"token_name"`
+	Decimal     uint8     `json:"decimal"`
+	PredictFee  *big.Int  `json:"predict_fee" gorm:"serializer:u256"`
+	BaseFee     *big.Int  `json:"base_fee" gorm:"serializer:u256"`
+	GasPrice    *big.Int  `json:"gas_price" gorm:"serializer:u256"`
+	PriorityFee *big.Int  `json:"priority_fee" gorm:"serializer:u256"`
+	Timestamp   uint64    `json:"timestamp"`
 }
 
 func (GasFee) TableName() string {
@@ -34,29 +37,22 @@ type GasFeeDB interface {
 
 type GasFeeView interface {
 	QueryGasFees(chainId string) (*GasFee, error)
+	QueryGasFeeList() ([]GasFee, error)
 }
 
 func NewGasFeeDB(db *gorm.DB) GasFeeDB {
 	return &gasFeeDB{gorm: db}
 }
 
+// StoreOrUpdateGasFee upserts the single gas fee row of the chain.
 func (db *gasFeeDB) StoreOrUpdateGasFee(gasFee *GasFee) error {
-	var gasFeeRecord GasFee
-	err := db.gorm.Table("gas_fee").Where("chain_id = ?", gasFee.ChainId.String()).Take(&gasFeeRecord).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			result := db.gorm.Table("gas_fee").Omit("guid").Create(gasFee)
-			return result.Error
-		}
-	}
-	gasFeeRecord.TokenName = gasFee.TokenName
-	gasFeeRecord.PredictFee = gasFee.PredictFee
-	gasFeeRecord.Timestamp = gasFee.Timestamp
-	gasFeeRecord.Decimal = gasFee.Decimal
-	err = db.gorm.Table("gas_fee").Save(gasFeeRecord).Error
-	if err != nil {
-		log.Error("save gas fee record fail", "err", err)
-		return err
+	result := db.gorm.Table("gas_fee").Omit("guid").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_name", "predict_fee", "decimal", "base_fee", "gas_price", "priority_fee", "timestamp"}),
+	}).Create(gasFee)
+	if result.Error != nil {
+		log.Error("store or update gas fee fail", "err", result.Error)
+		return result.Error
 	}
 	return nil
 }
@@ -70,3 +66,13 @@ func (db *gasFeeDB) QueryGasFees(chainId string) (*GasFee, error) {
 	}
 	return &gasFee, nil
 }
+
+func (db *gasFeeDB) QueryGasFeeList() ([]GasFee, error) {
+	var gasFeeList []GasFee
+	err := db.gorm.Table("gas_fee").Order("chain_id asc").Find(&gasFeeList).Error
+	if err != nil {
+		log.Error("get gas fee list fail", "err", err)
+		return nil, err
+	}
+	return gasFeeList, nil
+}
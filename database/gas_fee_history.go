@@ -0,0 +1,83 @@
+package database
+
+import (
+	"math/big"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// GasFeeHistory is the fee summary of a single sampled block, backing eth_feeHistory.
+type GasFeeHistory struct {
+	GUID           uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	ChainId        *big.Int  `json:"chain_id" gorm:"serializer:u256"`
+	BlockNumber    *big.Int  `json:"block_number" gorm:"serializer:u256"`
+	BaseFee        *big.Int  `json:"base_fee" gorm:"serializer:u256"`
+	GasUsedRatio   string    `json:"gas_used_ratio"`
+	PriorityFeeMin *big.Int  `json:"priority_fee_min" gorm:"serializer:u256"`
+	PriorityFeeP25 *big.Int  `json:"priority_fee_p25" gorm:"serializer:u256"`
+	PriorityFeeP50 *big.Int  `json:"priority_fee_p50" gorm:"serializer:u256"`
+	PriorityFeeP75 *big.Int  `json:"priority_fee_p75" gorm:"serializer:u256"`
+	PriorityFeeMax *big.Int  `json:"priority_fee_max" gorm:"serializer:u256"`
+	Timestamp      uint64    `json:"timestamp"`
+}
+
+func (GasFeeHistory) TableName() string {
+	return "gas_fee_history"
+}
+
+type gasFeeHistoryDB struct {
+	gorm *gorm.DB
+}
+
+type GasFeeHistoryDB interface {
+	GasFeeHistoryView
+	StoreGasFeeHistories(histories []GasFeeHistory) error
+	DeleteGasFeeHistoriesBefore(chainId string, blockNumber *big.Int) error
+}
+
+type GasFeeHistoryView interface {
+	QueryGasFeeHistories(chainId string, newestBlock *big.Int, limit int) ([]GasFeeHistory, error)
+}
+
+func NewGasFeeHistoryDB(db *gorm.DB) GasFeeHistoryDB {
+	return &gasFeeHistoryDB{gorm: db}
+}
+
+// StoreGasFeeHistories inserts the block summaries, skipping blocks already sampled.
+func (db *gasFeeHistoryDB) StoreGasFeeHistories(histories []GasFeeHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	result := db.gorm.Table("gas_fee_history").Omit("guid").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "chain_id"}, {Name: "block_number"}}, DoNothing: true}).
+		Create(&histories)
+	return result.Error
+}
+
+func (db *gasFeeHistoryDB) DeleteGasFeeHistoriesBefore(chainId string, blockNumber *big.Int) error {
+	result := db.gorm.Table("gas_fee_history").Where("chain_id = ? AND block_number < ?", chainId, blockNumber.String()).Delete(&GasFeeHistory{})
+	return result.Error
+}
+
+// QueryGasFeeHistories returns up to limit block summaries at or below newestBlock,
+// oldest first. A nil newestBlock means the latest sampled block.
+func (db *gasFeeHistoryDB) QueryGasFeeHistories(chainId string, newestBlock *big.Int, limit int) ([]GasFeeHistory, error) {
+	var histories []GasFeeHistory
+	query := db.gorm.Table("gas_fee_history").Where("chain_id = ?", chainId)
+	if newestBlock != nil {
+		query = query.Where("block_number <= ?", newestBlock.String())
+	}
+	err := query.Order("block_number desc").Limit(limit).Find(&histories).Error
+	if err != nil {
+		log.Error("get gas fee history fail", "err", err)
+		return nil, err
+	}
+	for i, j := 0, len(histories)-1; i < j; i, j = i+1, j-1 {
+		histories[i], histories[j] = histories[j], histories[i]
+	}
+	return histories, nil
+}
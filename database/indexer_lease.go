@@ -0,0 +1,77 @@
+package database
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+// dbNowMillis is the database clock in unix milliseconds. Leases are timed
+// on it so replicas with skewed clocks agree on expiry.
+const dbNowMillis = "(EXTRACT(EPOCH FROM clock_timestamp()) * 1000)::BIGINT"
+
+type IndexerLease struct {
+	LeaseKey  string `json:"lease_key" gorm:"primaryKey"`
+	Holder    string `json:"holder"`
+	ExpireAt  int64  `json:"expire_at"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+func (IndexerLease) TableName() string {
+	return "indexer_lease"
+}
+
+type indexerLeaseDB struct {
+	gorm *gorm.DB
+}
+
+type IndexerLeaseDB interface {
+	IndexerLeaseView
+	AcquireLease(leaseKey string, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(leaseKey string, holder string) error
+}
+
+type IndexerLeaseView interface {
+	CountLiveLeases(keyPrefix string) (int64, error)
+}
+
+func NewIndexerLeaseDB(db *gorm.DB) IndexerLeaseDB {
+	return &indexerLeaseDB{gorm: db}
+}
+
+// AcquireLease takes leaseKey for holder, or extends it when holder already
+// owns it, and reports whether holder owns the lease for the next ttl. A lease
+// held by someone else is only taken over once it expired.
+func (db *indexerLeaseDB) AcquireLease(leaseKey string, holder string, ttl time.Duration) (bool, error) {
+	var holders []string
+	err := db.gorm.Raw(`INSERT INTO indexer_lease (lease_key, holder, expire_at, timestamp) VALUES (?, ?, `+dbNowMillis+` + ?, ?)
+		ON CONFLICT (lease_key) DO UPDATE SET holder = EXCLUDED.holder, expire_at = EXCLUDED.expire_at, timestamp = EXCLUDED.timestamp
+		WHERE indexer_lease.holder = EXCLUDED.holder OR indexer_lease.expire_at < `+dbNowMillis+`
+		RETURNING holder`, leaseKey, holder, ttl.Milliseconds(), time.Now().Unix()).Scan(&holders).Error
+	if err != nil {
+		log.Error("acquire indexer lease fail", "leaseKey", leaseKey, "err", err)
+		return false, err
+	}
+	return len(holders) > 0, nil
+}
+
+func (db *indexerLeaseDB) ReleaseLease(leaseKey string, holder string) error {
+	err := db.gorm.Table("indexer_lease").Where("lease_key = ? AND holder = ?", leaseKey, holder).Delete(&IndexerLease{}).Error
+	if err != nil {
+		log.Error("release indexer lease fail", "leaseKey", leaseKey, "err", err)
+		return err
+	}
+	return nil
+}
+
+// CountLiveLeases counts the unexpired leases whose key starts with keyPrefix.
+func (db *indexerLeaseDB) CountLiveLeases(keyPrefix string) (int64, error) {
+	var count int64
+	err := db.gorm.Table("indexer_lease").Where("lease_key LIKE ? AND expire_at >= "+dbNowMillis, keyPrefix+"%").Count(&count).Error
+	if err != nil {
+		log.Error("count indexer leases fail", "err", err)
+		return 0, err
+	}
+	return count, nil
+}
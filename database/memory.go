@@ -0,0 +1,414 @@
+package database
+
+import (
+	"maps"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryStore keeps every table in process memory. It backs the memory driver
+// for tests and single process deployments that can afford to start empty.
+type memoryStore struct {
+	*memoryState
+	// undo reverts the writes of the transaction the store was handed to, it
+	// is nil outside of a transaction
+	undo *[]func()
+}
+
+type memoryState struct {
+	mu sync.Mutex
+	memoryTables
+	transactionsMu sync.Mutex
+}
+
+// memoryTables are the tables of a memoryStore. Stored rows are replaced and
+// never modified in place.
+type memoryTables struct {
+	gasFees       map[string]GasFee
+	gasFeeHistory map[string]map[string]GasFeeHistory // chain id -> block number -> summary
+	tokenPrices   map[string]TokenPrice               // symbol and quote currency -> price
+	apiQuotas     map[string]ApiQuota
+	chainConfigs  map[string]ChainConfig
+	tokenConfigs  map[string]TokenConfig
+	indexerLeases map[string]IndexerLease
+}
+
+// NewMemoryDB returns an empty in-memory DB. Transactions run one at a time
+// and a failed one reverts only the rows it wrote, writes made outside of it
+// in the meantime are kept.
+func NewMemoryDB() *DB {
+	store := &memoryStore{memoryState: &memoryState{memoryTables: memoryTables{
+		gasFees:       make(map[string]GasFee),
+		gasFeeHistory: make(map[string]map[string]GasFeeHistory),
+		tokenPrices:   make(map[string]TokenPrice),
+		apiQuotas:     make(map[string]ApiQuota),
+		chainConfigs:  make(map[string]ChainConfig),
+		tokenConfigs:  make(map[string]TokenConfig),
+		indexerLeases: make(map[string]IndexerLease),
+	}}}
+	db := newMemoryDB(store)
+	db.transaction = func(fn func(db *DB) error) error {
+		store.transactionsMu.Lock()
+		defer store.transactionsMu.Unlock()
+		var undo []func()
+		if err := fn(newMemoryDB(&memoryStore{memoryState: store.memoryState, undo: &undo})); err != nil {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+			return err
+		}
+		return nil
+	}
+	return db
+}
+
+func newMemoryDB(store *memoryStore) *DB {
+	return &DB{
+		GasFee:        store,
+		GasFeeHistory: store,
+		TokenPrice:    store,
+		ApiConsumer:   store,
+		ApiQuota:      store,
+		ChainConfig:   store,
+		TokenConfig:   store,
+		IndexerLease:  store,
+	}
+}
+
+// setRow stores row under key of table, m.mu held. Within a transaction it
+// logs how to revert the write.
+func setRow[K comparable, V any](m *memoryStore, table map[K]V, key K, row V) {
+	logUndo(m, table, key, row, true)
+	table[key] = row
+}
+
+// deleteRow removes key from table, m.mu held. Within a transaction it logs
+// how to revert the delete.
+func deleteRow[K comparable, V any](m *memoryStore, table map[K]V, key K) {
+	var zero V
+	logUndo(m, table, key, zero, false)
+	delete(table, key)
+}
+
+// logUndo records how to restore the row of key as it is before the write of
+// row, or its removal when present is false. A row written again since then,
+// by another writer, is left alone on rollback.
+func logUndo[K comparable, V any](m *memoryStore, table map[K]V, key K, row V, present bool) {
+	if m.undo == nil {
+		return
+	}
+	previous, existed := table[key]
+	*m.undo = append(*m.undo, func() {
+		current, ok := table[key]
+		if ok != present || (ok && !reflect.DeepEqual(current, row)) {
+			return
+		}
+		if existed {
+			table[key] = previous
+		} else {
+			delete(table, key)
+		}
+	})
+}
+
+func copyBig(v *big.Int) *big.Int {
+	if v == nil {
+		return nil
+	}
+	return new(big.Int).Set(v)
+}
+
+func copyRat(v *big.Rat) *big.Rat {
+	if v == nil {
+		return nil
+	}
+	return new(big.Rat).Set(v)
+}
+
+func (m *memoryStore) StoreOrUpdateGasFee(gasFee *GasFee) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *gasFee
+	stored.ChainId = copyBig(gasFee.ChainId)
+	stored.PredictFee = copyBig(gasFee.PredictFee)
+	stored.BaseFee = copyBig(gasFee.BaseFee)
+	stored.GasPrice = copyBig(gasFee.GasPrice)
+	stored.PriorityFee = copyBig(gasFee.PriorityFee)
+	stored.GUID = uuid.New()
+	if existing, ok := m.gasFees[gasFee.ChainId.String()]; ok {
+		stored.GUID = existing.GUID
+	}
+	setRow(m, m.gasFees, gasFee.ChainId.String(), stored)
+	return nil
+}
+
+func (m *memoryStore) QueryGasFees(chainId string) (*GasFee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	gasFee, ok := m.gasFees[chainId]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &gasFee, nil
+}
+
+func (m *memoryStore) QueryGasFeeList() ([]GasFee, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	gasFeeList := make([]GasFee, 0, len(m.gasFees))
+	for _, gasFee := range m.gasFees {
+		gasFeeList = append(gasFeeList, gasFee)
+	}
+	sort.Slice(gasFeeList, func(i, j int) bool {
+		return gasFeeList[i].ChainId.Cmp(gasFeeList[j].ChainId) < 0
+	})
+	return gasFeeList, nil
+}
+
+func (m *memoryStore) StoreGasFeeHistories(histories []GasFeeHistory) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, history := range histories {
+		blocks, ok := m.gasFeeHistory[history.ChainId.String()]
+		if !ok {
+			blocks = make(map[string]GasFeeHistory)
+			m.gasFeeHistory[history.ChainId.String()] = blocks
+		}
+		if _, ok := blocks[history.BlockNumber.String()]; ok {
+			continue
+		}
+		history.GUID = uuid.New()
+		setRow(m, blocks, history.BlockNumber.String(), history)
+	}
+	return nil
+}
+
+func (m *memoryStore) DeleteGasFeeHistoriesBefore(chainId string, blockNumber *big.Int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, history := range m.gasFeeHistory[chainId] {
+		if history.BlockNumber.Cmp(blockNumber) < 0 {
+			deleteRow(m, m.gasFeeHistory[chainId], key)
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) QueryGasFeeHistories(chainId string, newestBlock *big.Int, limit int) ([]GasFeeHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var histories []GasFeeHistory
+	for _, history := range m.gasFeeHistory[chainId] {
+		if newestBlock == nil || history.BlockNumber.Cmp(newestBlock) <= 0 {
+			histories = append(histories, history)
+		}
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].BlockNumber.Cmp(histories[j].BlockNumber) < 0
+	})
+	if len(histories) > limit {
+		histories = histories[len(histories)-limit:]
+	}
+	return histories, nil
+}
+
+// StoreOrUpdateTokenPrice keeps the name and decimal of the first write, like
+// the postgres upsert which only updates the price, its derivation and timestamp.
+func (m *memoryStore) StoreOrUpdateTokenPrice(tokenPrice *TokenPrice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := tokenPrice.TokenSymbol + "\x00" + tokenPrice.QuoteCurrency
+	stored, ok := m.tokenPrices[key]
+	if !ok {
+		stored = *tokenPrice
+		stored.GUID = uuid.New()
+	}
+	stored.SkeyeSymbol = tokenPrice.SkeyeSymbol
+	stored.MarketPrice = copyRat(tokenPrice.MarketPrice)
+	stored.DerivationPath = tokenPrice.DerivationPath
+	stored.Confidence = tokenPrice.Confidence
+	stored.PriceState = tokenPrice.PriceState
+	stored.PriceStateReason = tokenPrice.PriceStateReason
+	stored.Timestamp = tokenPrice.Timestamp
+	setRow(m, m.tokenPrices, key, stored)
+	return nil
+}
+
+func (m *memoryStore) UpdateTokenPriceState(symbol string, quoteCurrency string, state string, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := symbol + "\x00" + quoteCurrency
+	if tokenPrice, ok := m.tokenPrices[key]; ok {
+		tokenPrice.PriceState = state
+		tokenPrice.PriceStateReason = reason
+		setRow(m, m.tokenPrices, key, tokenPrice)
+	}
+	return nil
+}
+
+func (m *memoryStore) QueryTokenPrices(symbol string, quoteCurrency string) (*TokenPrice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tokenPrice, ok := m.tokenPrices[symbol+"\x00"+quoteCurrency]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tokenPrice, nil
+}
+
+// QueryApiConsumer finds nothing: the memory driver only knows the consumers
+// of the config file.
+func (m *memoryStore) QueryApiConsumer(token string) (*ApiConsumer, error) {
+	return nil, nil
+}
+
+// StoreApiConsumerAudit drops the audit, the memory driver does not keep an
+// ever growing trail.
+func (m *memoryStore) StoreApiConsumerAudit(audit *ApiConsumerAudit) error {
+	return nil
+}
+
+func (m *memoryStore) IncreaseApiQuota(quotaKey string, day string, count uint64) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := quotaKey + "\x00" + day
+	apiQuota, ok := m.apiQuotas[key]
+	if !ok {
+		apiQuota = ApiQuota{GUID: uuid.New(), QuotaKey: quotaKey, Day: day}
+	}
+	apiQuota.RequestCount += count
+	apiQuota.Timestamp = uint64(time.Now().Unix())
+	setRow(m, m.apiQuotas, key, apiQuota)
+	return apiQuota.RequestCount, nil
+}
+
+func (m *memoryStore) QueryApiQuota(quotaKey string, day string) (*ApiQuota, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	apiQuota, ok := m.apiQuotas[quotaKey+"\x00"+day]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &apiQuota, nil
+}
+
+func (m *memoryStore) StoreOrUpdateChainConfig(chainConfig *ChainConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *chainConfig
+	stored.ChainId = copyBig(chainConfig.ChainId)
+	stored.RpcUrls = append([]string(nil), chainConfig.RpcUrls...)
+	stored.GUID = uuid.New()
+	if existing, ok := m.chainConfigs[chainConfig.ChainId.String()]; ok {
+		stored.GUID = existing.GUID
+	}
+	setRow(m, m.chainConfigs, chainConfig.ChainId.String(), stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteChainConfig(chainId *big.Int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.chainConfigs[chainId.String()]
+	deleteRow(m, m.chainConfigs, chainId.String())
+	return ok, nil
+}
+
+func (m *memoryStore) QueryChainConfigList() ([]ChainConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chainConfigList := make([]ChainConfig, 0, len(m.chainConfigs))
+	for _, chainConfig := range m.chainConfigs {
+		chainConfig.RpcUrls = append([]string(nil), chainConfig.RpcUrls...)
+		chainConfigList = append(chainConfigList, chainConfig)
+	}
+	sort.Slice(chainConfigList, func(i, j int) bool {
+		return chainConfigList[i].ChainId.Cmp(chainConfigList[j].ChainId) < 0
+	})
+	return chainConfigList, nil
+}
+
+func (m *memoryStore) StoreOrUpdateTokenConfig(tokenConfig *TokenConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *tokenConfig
+	stored.Aliases = append([]string(nil), tokenConfig.Aliases...)
+	stored.Addresses = maps.Clone(tokenConfig.Addresses)
+	stored.GUID = uuid.New()
+	if existing, ok := m.tokenConfigs[tokenConfig.Symbol]; ok {
+		stored.GUID = existing.GUID
+	}
+	setRow(m, m.tokenConfigs, tokenConfig.Symbol, stored)
+	return nil
+}
+
+func (m *memoryStore) DeleteTokenConfig(symbol string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.tokenConfigs[symbol]
+	deleteRow(m, m.tokenConfigs, symbol)
+	return ok, nil
+}
+
+func (m *memoryStore) QueryTokenConfigList() ([]TokenConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tokenConfigList := make([]TokenConfig, 0, len(m.tokenConfigs))
+	for _, tokenConfig := range m.tokenConfigs {
+		tokenConfigList = append(tokenConfigList, tokenConfig)
+	}
+	sort.Slice(tokenConfigList, func(i, j int) bool {
+		return tokenConfigList[i].Symbol < tokenConfigList[j].Symbol
+	})
+	return tokenConfigList, nil
+}
+
+// AcquireLease times leases on the local clock, which is all replicas share
+// when they share one process.
+func (m *memoryStore) AcquireLease(leaseKey string, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	lease, ok := m.indexerLeases[leaseKey]
+	if ok && lease.Holder != holder && lease.ExpireAt >= now.UnixMilli() {
+		return false, nil
+	}
+	setRow(m, m.indexerLeases, leaseKey, IndexerLease{
+		LeaseKey:  leaseKey,
+		Holder:    holder,
+		ExpireAt:  now.Add(ttl).UnixMilli(),
+		Timestamp: uint64(now.Unix()),
+	})
+	return true, nil
+}
+
+func (m *memoryStore) ReleaseLease(leaseKey string, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lease, ok := m.indexerLeases[leaseKey]; ok && lease.Holder == holder {
+		deleteRow(m, m.indexerLeases, leaseKey)
+	}
+	return nil
+}
+
+func (m *memoryStore) CountLiveLeases(keyPrefix string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	now := time.Now().UnixMilli()
+	for key, lease := range m.indexerLeases {
+		if strings.HasPrefix(key, keyPrefix) && lease.ExpireAt >= now {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMemoryRollbackKeepsConcurrentWrites(t *testing.T) {
+	db := NewMemoryDB()
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(1), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(1)}))
+
+	failure := errors.New("round failed")
+	err := db.Transaction(func(tx *DB) error {
+		if err := tx.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "USD", Decimal: 18, MarketPrice: big.NewRat(1, 1)}); err != nil {
+			return err
+		}
+		if err := tx.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(2)}); err != nil {
+			return err
+		}
+
+		// the synchronizer, elector and quota counter keep writing meanwhile
+		done := make(chan error, 1)
+		go func() {
+			done <- errors.Join(
+				db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(1), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(3)}),
+				db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(4)}),
+				func() error {
+					_, err := db.IndexerLease.AcquireLease("worker", "a", time.Minute)
+					return err
+				}(),
+				func() error {
+					_, err := db.ApiQuota.IncreaseApiQuota("consumer", "2026-10-19", 1)
+					return err
+				}(),
+			)
+		}()
+		if err := <-done; err != nil {
+			return err
+		}
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	_, err = db.TokenPrice.QueryTokenPrices("eth", "USD")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound, "the write of the transaction is rolled back")
+	gasFee, err := db.GasFee.QueryGasFees("1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), gasFee.PredictFee.Int64())
+	gasFee, err = db.GasFee.QueryGasFees("10")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), gasFee.PredictFee.Int64(), "a row written again outside the transaction is kept")
+	count, err := db.IndexerLease.CountLiveLeases("worker")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	apiQuota, err := db.ApiQuota.QueryApiQuota("consumer", "2026-10-19")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), apiQuota.RequestCount)
+}
@@ -0,0 +1,289 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+// migrationLockId serializes migration runs from several processes.
+const migrationLockId = 7_302_654_158
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  uint64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type SchemaMigration struct {
+	Version   uint64 `json:"version" gorm:"primaryKey"`
+	Name      string `json:"name"`
+	Checksum  string `json:"checksum"`
+	AppliedAt uint64 `json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt uint64
+	// Modified reports an applied migration whose up file changed since
+	Modified bool
+	// Missing reports an applied migration without a file any more
+	Missing bool
+}
+
+// LoadMigrations reads the <version>_<name>.up.sql and .down.sql files of
+// fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrateUp applies the pending migrations of fsys in version order, each in
+// its own transaction, and returns the ones applied. It refuses to run when an
+// applied migration was edited.
+func (db *DB) MigrateUp(fsys fs.FS) ([]Migration, error) {
+	if err := db.requirePostgres(); err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := db.migrationStatus(migrations)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if status.Modified {
+			return nil, fmt.Errorf("applied migration %d_%s was modified, add a new migration instead", status.Version, status.Name)
+		}
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		done, err := db.applyMigration(migration)
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			log.Info("migration applied", "version", migration.Version, "name", migration.Name)
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+func (db *DB) applyMigration(migration Migration) (bool, error) {
+	done := false
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockId).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Table("schema_migrations").Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = true
+		return tx.Table("schema_migrations").Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: uint64(time.Now().Unix()),
+		}).Error
+	})
+	return done, err
+}
+
+// MigrateDown reverts the last n applied migrations, newest first, and returns
+// the ones reverted.
+func (db *DB) MigrateDown(fsys fs.FS, n int) ([]Migration, error) {
+	if err := db.requirePostgres(); err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[uint64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	if err := db.ensureSchemaMigrations(); err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := 0; i < n; i++ {
+		migration, done, err := db.revertLatestMigration(byVersion)
+		if err != nil {
+			return reverted, err
+		}
+		if !done {
+			break
+		}
+		log.Info("migration reverted", "version", migration.Version, "name", migration.Name)
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+func (db *DB) revertLatestMigration(byVersion map[uint64]Migration) (Migration, bool, error) {
+	var migration Migration
+	done := false
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockId).Error; err != nil {
+			return err
+		}
+		var latest []SchemaMigration
+		if err := tx.Table("schema_migrations").Order("version desc").Limit(1).Find(&latest).Error; err != nil {
+			return err
+		}
+		if len(latest) == 0 {
+			return nil
+		}
+		var ok bool
+		migration, ok = byVersion[latest[0].Version]
+		if !ok {
+			return fmt.Errorf("applied migration %d_%s has no file", latest[0].Version, latest[0].Name)
+		}
+		if migration.Down == "" {
+			return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = true
+		return tx.Table("schema_migrations").Where("version = ?", migration.Version).Delete(&SchemaMigration{}).Error
+	})
+	return migration, done, err
+}
+
+// MigrationStatus lists every known or applied migration with its state.
+func (db *DB) MigrationStatus(fsys fs.FS) ([]MigrationStatus, error) {
+	if err := db.requirePostgres(); err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return db.migrationStatus(migrations)
+}
+
+func (db *DB) migrationStatus(migrations []Migration) ([]MigrationStatus, error) {
+	if err := db.ensureSchemaMigrations(); err != nil {
+		return nil, err
+	}
+	var appliedList []SchemaMigration
+	if err := db.gorm.Table("schema_migrations").Find(&appliedList).Error; err != nil {
+		log.Error("get schema migrations fail", "err", err)
+		return nil, err
+	}
+	applied := make(map[uint64]SchemaMigration, len(appliedList))
+	for _, schemaMigration := range appliedList {
+		applied[schemaMigration.Version] = schemaMigration
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if schemaMigration, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = schemaMigration.AppliedAt
+			status.Modified = schemaMigration.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, schemaMigration := range applied {
+		statuses = append(statuses, MigrationStatus{
+			Version:   schemaMigration.Version,
+			Name:      schemaMigration.Name,
+			Applied:   true,
+			AppliedAt: schemaMigration.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (db *DB) ensureSchemaMigrations() error {
+	return db.gorm.Exec(`create table if not exists schema_migrations(
+    version                BIGINT PRIMARY KEY,
+    name                   VARCHAR NOT NULL,
+    checksum               VARCHAR NOT NULL,
+    applied_at             INTEGER
+)`).Error
+}
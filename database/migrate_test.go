@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/migrations"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	for i, migration := range loaded {
+		require.Equal(t, uint64(i+1), migration.Version, "migration versions must be contiguous")
+		require.NotEmpty(t, migration.Down, "migration %d_%s has no down file", migration.Version, migration.Name)
+		require.Len(t, migration.Checksum, 64)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(fstest.MapFS{
+		"00002_second.up.sql":   {Data: []byte("create table b();")},
+		"00001_first.up.sql":    {Data: []byte("create table a();")},
+		"00001_first.down.sql":  {Data: []byte("drop table a;")},
+		"README.md":             {Data: []byte("not a migration")},
+		"00003_third.down.sql":  {Data: []byte("drop table c;")},
+		"00003_third.up.sql":    {Data: []byte("create table c();")},
+		"00010_tenth.up.sql":    {Data: []byte("create table j();")},
+		"00010_tenth.down.sql":  {Data: []byte("drop table j;")},
+		"00004_fourth.down.sql": {Data: []byte("")},
+		"00004_fourth.up.sql":   {Data: []byte("select 1;")},
+	})
+	require.NoError(t, err)
+	var versions []uint64
+	for _, migration := range loaded {
+		versions = append(versions, migration.Version)
+	}
+	require.Equal(t, []uint64{1, 2, 3, 4, 10}, versions)
+	require.Equal(t, "first", loaded[0].Name)
+	require.Equal(t, "drop table a;", loaded[0].Down)
+	require.Empty(t, loaded[1].Down)
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"00001_first.up.sql": {Data: []byte("select 1;")},
+		"00001_other.up.sql": {Data: []byte("select 2;")},
+	})
+	require.ErrorContains(t, err, "migration version 1 is used by both")
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"00001_first.down.sql": {Data: []byte("select 1;")},
+	})
+	require.ErrorContains(t, err, "has no up file")
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/migrations"
+)
+
+// testDB connects to the Postgres named by the GAS_ORACLE_TEST_DB_* variables,
+// migrates it and empties the tables under test. The database is wiped, so
+// never point it at real data. Tests are skipped when no database is set, e.g.
+//
+//	docker run -d -p 5432:5432 -e POSTGRES_PASSWORD=postgres postgres:16
+//	GAS_ORACLE_TEST_DB_HOST=127.0.0.1 GAS_ORACLE_TEST_DB_USER=postgres \
+//	  GAS_ORACLE_TEST_DB_PASSWORD=postgres go test ./database/
+func testDB(t *testing.T) *DB {
+	t.Helper()
+	host := os.Getenv("GAS_ORACLE_TEST_DB_HOST")
+	if host == "" {
+		t.Skip("GAS_ORACLE_TEST_DB_HOST not set, skipping postgres tests")
+	}
+	port, _ := strconv.Atoi(os.Getenv("GAS_ORACLE_TEST_DB_PORT"))
+	name := os.Getenv("GAS_ORACLE_TEST_DB_NAME")
+	if name == "" {
+		name = "postgres"
+	}
+
+	db, err := NewDB(context.Background(), config.Database{
+		DbHost:     host,
+		DbPort:     port,
+		DbName:     name,
+		DbUser:     os.Getenv("GAS_ORACLE_TEST_DB_USER"),
+		DbPassword: os.Getenv("GAS_ORACLE_TEST_DB_PASSWORD"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	_, err = db.MigrateUp(migrations.FS)
+	require.NoError(t, err)
+	require.NoError(t, db.gorm.Exec("TRUNCATE gas_fee, gas_fee_history, token_price, api_quota, chain_config, token_config, indexer_lease").Error)
+	return db
+}
+
+func TestConcurrentGasFeeUpsert(t *testing.T) {
+	db := testDB(t)
+
+	const writers = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.GasFee.StoreOrUpdateGasFee(&GasFee{
+				ChainId:    big.NewInt(1),
+				TokenName:  "ETH",
+				Decimal:    18,
+				PredictFee: big.NewInt(int64(1000 + i)),
+				Timestamp:  uint64(i),
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var count int64
+	require.NoError(t, db.gorm.Table("gas_fee").Where("chain_id = ?", "1").Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	gasFee, err := db.GasFee.QueryGasFees("1")
+	require.NoError(t, err)
+	require.Equal(t, strconv.Itoa(1000+int(gasFee.Timestamp)), gasFee.PredictFee.String(), "fee and timestamp must come from the same write")
+}
+
+func TestConcurrentTokenPriceUpsert(t *testing.T) {
+	db := testDB(t)
+
+	const writers = 32
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			symbol := "eth"
+			if i%2 == 1 {
+				symbol = "btc"
+			}
+			errs <- db.TokenPrice.StoreOrUpdateTokenPrice(&TokenPrice{
+				TokenName:     symbol,
+				TokenSymbol:   symbol,
+				QuoteCurrency: "USD",
+				Decimal:       18,
+				MarketPrice:   big.NewRat(int64(2*i+1), 2),
+				Timestamp:     uint64(i),
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	for _, symbol := range []string{"eth", "btc"} {
+		var count int64
+		require.NoError(t, db.gorm.Table("token_price").Where("token_symbol = ?", symbol).Count(&count).Error)
+		require.Equal(t, int64(1), count, symbol)
+
+		tokenPrice, err := db.TokenPrice.QueryTokenPrices(symbol, "USD")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%d.5", tokenPrice.Timestamp), tokenPrice.MarketPriceString())
+	}
+}
+
+func TestUpsertUpdatesExistingRow(t *testing.T) {
+	db := testDB(t)
+
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(1), Timestamp: 1}))
+	first, err := db.GasFee.QueryGasFees("10")
+	require.NoError(t, err)
+
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&GasFee{ChainId: big.NewInt(10), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(2), Timestamp: 2, BaseFee: big.NewInt(7)}))
+	second, err := db.GasFee.QueryGasFees("10")
+	require.NoError(t, err)
+	require.Equal(t, first.GUID, second.GUID)
+	require.Equal(t, big.NewInt(2), second.PredictFee)
+	require.Equal(t, uint64(2), second.Timestamp)
+	require.Equal(t, big.NewInt(7), second.BaseFee)
+}
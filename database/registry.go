@@ -0,0 +1,151 @@
+package database
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+// ApplyRegistry merges the chain_config and token_config tables into cfg,
+// which must be valid. Enabled rows add to or replace the yaml entry with the
+// same chain id or symbol, disabled rows remove it. A row that would leave cfg
+// invalid, such as a chain whose native token names no symbol, is logged and
+// skipped. Token rows are merged first so chain rows may name their symbols.
+func (db *DB) ApplyRegistry(cfg *config.Config) error {
+	chainConfigList, err := db.ChainConfig.QueryChainConfigList()
+	if err != nil {
+		return err
+	}
+	tokenConfigList, err := db.TokenConfig.QueryTokenConfigList()
+	if err != nil {
+		return err
+	}
+	for _, tokenConfig := range tokenConfigList {
+		candidate := *cfg
+		candidate.Symbols = mergeTokenConfigs(cfg.Symbols, []TokenConfig{tokenConfig})
+		if err := candidate.Validate(); err != nil {
+			log.Error("token config is invalid, skip it", "symbol", tokenConfig.Symbol, "err", err)
+			continue
+		}
+		*cfg = candidate
+	}
+	for _, chainConfig := range chainConfigList {
+		candidate := *cfg
+		candidate.RPCs = mergeChainConfigs(cfg.RPCs, []ChainConfig{chainConfig})
+		if err := candidate.Validate(); err != nil {
+			log.Error("chain config is invalid, skip it", "chainId", chainConfig.ChainId, "err", err)
+			continue
+		}
+		*cfg = candidate
+	}
+	return nil
+}
+
+// MergeRegistry merges the given registry rows into cfg without checking
+// them, for callers validating the result as a whole.
+func MergeRegistry(cfg *config.Config, chainConfigList []ChainConfig, tokenConfigList []TokenConfig) {
+	cfg.RPCs = mergeChainConfigs(cfg.RPCs, chainConfigList)
+	cfg.Symbols = mergeTokenConfigs(cfg.Symbols, tokenConfigList)
+}
+
+func mergeChainConfigs(rpcs []*config.RPC, chainConfigList []ChainConfig) []*config.RPC {
+	merged := append([]*config.RPC(nil), rpcs...)
+	index := make(map[uint64]int, len(rpcs))
+	for i, rpc := range merged {
+		index[rpc.ChainId] = i
+	}
+	removed := make(map[uint64]bool)
+	for _, chainConfig := range chainConfigList {
+		chainId := chainConfig.ChainId.Uint64()
+		if !chainConfig.Enabled {
+			removed[chainId] = true
+			continue
+		}
+		rpc := chainConfig.RPC()
+		if rpc == nil {
+			log.Warn("chain config has no rpc url, skip it", "chainId", chainId)
+			continue
+		}
+		if i, ok := index[chainId]; ok {
+			merged[i] = rpc
+		} else {
+			index[chainId] = len(merged)
+			merged = append(merged, rpc)
+		}
+	}
+
+	out := merged[:0]
+	for _, rpc := range merged {
+		if !removed[rpc.ChainId] {
+			out = append(out, rpc)
+		}
+	}
+	return out
+}
+
+func mergeTokenConfigs(symbols []config.Symbols, tokenConfigList []TokenConfig) []config.Symbols {
+	merged := append([]config.Symbols(nil), symbols...)
+	index := make(map[string]int, len(symbols))
+	for i, symbol := range merged {
+		index[symbol.Name] = i
+	}
+	removed := make(map[string]bool)
+	for _, tokenConfig := range tokenConfigList {
+		symbol := config.Symbols{
+			Name:        tokenConfig.Symbol,
+			Decimal:     tokenConfig.Decimal,
+			SkeyeSymbol: tokenConfig.SkeyeSymbol,
+			Aliases:     tokenConfig.Aliases,
+			Addresses:   tokenConfig.TokenAddresses(),
+		}
+		i, ok := index[symbol.Name]
+		switch {
+		case !tokenConfig.Enabled:
+			removed[symbol.Name] = true
+		case ok:
+			merged[i] = symbol
+		default:
+			index[symbol.Name] = len(merged)
+			merged = append(merged, symbol)
+		}
+	}
+
+	out := merged[:0]
+	for _, symbol := range merged {
+		if !removed[symbol.Name] {
+			out = append(out, symbol)
+		}
+	}
+	return out
+}
+
+// TokenAddresses returns the token contracts in the yaml form, ordered by chain id.
+func (c *TokenConfig) TokenAddresses() []config.TokenAddress {
+	var addresses []config.TokenAddress
+	for _, chainId := range slices.Sorted(maps.Keys(c.Addresses)) {
+		addresses = append(addresses, config.TokenAddress{ChainId: chainId, Address: c.Addresses[chainId]})
+	}
+	return addresses
+}
+
+// RPC converts an enabled chain config into the yaml form the oracle runs
+// from, or returns nil when it is disabled or has no rpc url.
+func (c *ChainConfig) RPC() *config.RPC {
+	if !c.Enabled || len(c.RpcUrls) == 0 {
+		return nil
+	}
+	return &config.RPC{
+		RpcUrl:        c.RpcUrls[0],
+		BackupRpcUrls: c.RpcUrls[1:],
+		ChainId:       c.ChainId.Uint64(),
+		ChainName:     c.ChainName,
+		NativeToken:   c.NativeToken,
+		Decimal:       c.Decimal,
+		BackOffset:    c.BackOffset,
+		LoopInternal:  time.Duration(c.LoopInterval) * time.Second,
+	}
+}
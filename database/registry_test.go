@@ -0,0 +1,93 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+func TestMergeChainConfigs(t *testing.T) {
+	rpcs := []*config.RPC{
+		{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH", Decimal: 18},
+		{RpcUrl: "http://op", ChainId: 10, NativeToken: "ETH", Decimal: 18},
+	}
+	chainConfigList := []ChainConfig{
+		{ChainId: big.NewInt(10), Enabled: false, RpcUrls: []string{"http://op2"}},
+		{ChainId: big.NewInt(1), Enabled: true, RpcUrls: []string{"http://eth2", "http://eth3"}, NativeToken: "ETH", Decimal: 18, LoopInterval: 3},
+		{ChainId: big.NewInt(56), Enabled: true, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18},
+		{ChainId: big.NewInt(97), Enabled: true, NativeToken: "BNB"},
+	}
+
+	merged := mergeChainConfigs(rpcs, chainConfigList)
+	require.Len(t, merged, 2)
+	require.Equal(t, uint64(1), merged[0].ChainId)
+	require.Equal(t, []string{"http://eth2", "http://eth3"}, merged[0].RpcUrls())
+	require.Equal(t, 3*time.Second, merged[0].LoopInternal)
+	require.Equal(t, uint64(56), merged[1].ChainId)
+	require.Equal(t, "http://eth", rpcs[0].RpcUrl, "yaml entries must not be modified")
+}
+
+func TestMergeTokenConfigs(t *testing.T) {
+	symbols := []config.Symbols{{Name: "eth", Decimal: 18}, {Name: "usdt", Decimal: 6}}
+	tokenConfigList := []TokenConfig{
+		{Symbol: "usdt", Enabled: false},
+		{Symbol: "weth", SkeyeSymbol: "eth", Decimal: 18, Enabled: true},
+		{Symbol: "pol", Decimal: 18, Enabled: true, Aliases: []string{"matic"},
+			Addresses: map[uint64]string{137: "0x0000000000000000000000000000000000001010", 1: "0x455e53CBB86018Ac2B8092FdCd39d8444aFFC3F6"}},
+	}
+
+	merged := mergeTokenConfigs(symbols, tokenConfigList)
+	require.Equal(t, []config.Symbols{
+		{Name: "eth", Decimal: 18},
+		{Name: "weth", Decimal: 18, SkeyeSymbol: "eth"},
+		{Name: "pol", Decimal: 18, Aliases: []string{"matic"}, Addresses: []config.TokenAddress{
+			{ChainId: 1, Address: "0x455e53CBB86018Ac2B8092FdCd39d8444aFFC3F6"},
+			{ChainId: 137, Address: "0x0000000000000000000000000000000000001010"},
+		}},
+	}, merged)
+	require.Len(t, symbols, 2)
+	require.Equal(t, "usdt", symbols[1].Name)
+}
+
+func TestApplyRegistrySkipsInvalidRows(t *testing.T) {
+	db := NewMemoryDB()
+	for _, tokenConfig := range []TokenConfig{
+		{Symbol: "bnb", Decimal: 18, Enabled: true},
+		// eth is the native token of the yaml chain
+		{Symbol: "eth", Enabled: false},
+		{Symbol: "weth", Decimal: 18, Enabled: true, Aliases: []string{"ETH"}},
+	} {
+		require.NoError(t, db.TokenConfig.StoreOrUpdateTokenConfig(&tokenConfig))
+	}
+	for _, chainConfig := range []ChainConfig{
+		{ChainId: big.NewInt(56), Enabled: true, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18},
+		{ChainId: big.NewInt(137), Enabled: true, RpcUrls: []string{"http://polygon"}, NativeToken: "POL", Decimal: 18},
+	} {
+		require.NoError(t, db.ChainConfig.StoreOrUpdateChainConfig(&chainConfig))
+	}
+
+	cfg := &config.Config{
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		SkyeyeUrl:    "http://skyeye",
+		Symbols:      []config.Symbols{{Name: "eth", Decimal: 18}},
+		RPCs:         []*config.RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH", Decimal: 18}},
+	}
+	require.NoError(t, db.ApplyRegistry(cfg))
+	require.NoError(t, cfg.Validate())
+
+	var symbols []string
+	for _, symbol := range cfg.Symbols {
+		symbols = append(symbols, symbol.Name)
+	}
+	require.ElementsMatch(t, []string{"eth", "bnb"}, symbols, "removing eth and claiming its name are skipped")
+	var chainIds []uint64
+	for _, rpc := range cfg.RPCs {
+		chainIds = append(chainIds, rpc.ChainId)
+	}
+	require.ElementsMatch(t, []uint64{1, 56}, chainIds, "a chain without its native token symbol is skipped")
+}
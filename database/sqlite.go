@@ -0,0 +1,518 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	// registers the pure Go sqlite driver, so no cgo toolchain is needed
+	_ "modernc.org/sqlite"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/common/bigint"
+)
+
+// sqliteDriverName is the database/sql driver the sqlite backend opens, as
+// registered by modernc.org/sqlite.
+const sqliteDriverName = "sqlite"
+
+// sqliteSchema mirrors the postgres tables. Chain ids and block numbers are
+// INTEGER so they order numerically, fees and prices are decimal TEXT.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS gas_fee (
+    guid         TEXT PRIMARY KEY,
+    chain_id     INTEGER NOT NULL UNIQUE,
+    token_name   TEXT NOT NULL,
+    decimal      INTEGER NOT NULL,
+    predict_fee  TEXT NOT NULL,
+    base_fee     TEXT,
+    gas_price    TEXT,
+    priority_fee TEXT,
+    timestamp    INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS gas_fee_history (
+    guid             TEXT PRIMARY KEY,
+    chain_id         INTEGER NOT NULL,
+    block_number     INTEGER NOT NULL,
+    base_fee         TEXT,
+    gas_used_ratio   TEXT NOT NULL,
+    priority_fee_min TEXT,
+    priority_fee_p25 TEXT,
+    priority_fee_p50 TEXT,
+    priority_fee_p75 TEXT,
+    priority_fee_max TEXT,
+    timestamp        INTEGER NOT NULL,
+    UNIQUE (chain_id, block_number)
+);
+CREATE TABLE IF NOT EXISTS token_price (
+    guid           TEXT PRIMARY KEY,
+    token_name     TEXT NOT NULL,
+    token_symbol   TEXT NOT NULL,
+    skeye_symbol   TEXT NOT NULL DEFAULT '',
+    quote_currency TEXT NOT NULL DEFAULT 'USD',
+    decimal        INTEGER NOT NULL,
+    market_price    TEXT NOT NULL,
+    derivation_path TEXT NOT NULL DEFAULT '',
+    confidence      REAL NOT NULL DEFAULT 1,
+    price_state     TEXT NOT NULL DEFAULT 'ok',
+    price_state_reason TEXT NOT NULL DEFAULT '',
+    timestamp       INTEGER NOT NULL,
+    UNIQUE (token_symbol, quote_currency)
+);
+CREATE TABLE IF NOT EXISTS api_consumer (
+    guid      TEXT PRIMARY KEY,
+    name      TEXT NOT NULL,
+    token     TEXT NOT NULL UNIQUE,
+    enabled   INTEGER NOT NULL,
+    expire_at INTEGER NOT NULL,
+    timestamp INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS api_consumer_audit (
+    guid          TEXT PRIMARY KEY,
+    consumer_name TEXT NOT NULL,
+    method        TEXT NOT NULL,
+    peer_addr     TEXT NOT NULL,
+    timestamp     INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS api_quota (
+    guid          TEXT PRIMARY KEY,
+    quota_key     TEXT NOT NULL,
+    day           TEXT NOT NULL,
+    request_count INTEGER NOT NULL,
+    timestamp     INTEGER NOT NULL,
+    UNIQUE (quota_key, day)
+);
+CREATE TABLE IF NOT EXISTS chain_config (
+    guid          TEXT PRIMARY KEY,
+    chain_id      INTEGER NOT NULL UNIQUE,
+    chain_name    TEXT NOT NULL,
+    rpc_urls      TEXT NOT NULL,
+    native_token  TEXT NOT NULL,
+    decimal       INTEGER NOT NULL,
+    enabled       INTEGER NOT NULL,
+    back_offset   INTEGER NOT NULL,
+    loop_interval INTEGER NOT NULL,
+    timestamp     INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS token_config (
+    guid         TEXT PRIMARY KEY,
+    symbol       TEXT NOT NULL UNIQUE,
+    skeye_symbol TEXT NOT NULL,
+    aliases      TEXT NOT NULL DEFAULT '[]',
+    addresses    TEXT NOT NULL DEFAULT '{}',
+    decimal      INTEGER NOT NULL,
+    enabled      INTEGER NOT NULL,
+    timestamp    INTEGER NOT NULL
+);
+`
+
+// sqlExecutor is what the sqlite tables run on, either the database or the
+// transaction they were handed.
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteStore implements the tables on a single sqlite file. Indexer leases
+// stay in memory: a sqlite file is not shared between replicas.
+type sqliteStore struct {
+	db sqlExecutor
+}
+
+// NewSQLiteDB opens the sqlite database at path and creates its tables.
+func NewSQLiteDB(ctx context.Context, path string) (*DB, error) {
+	sqlDB, err := sql.Open(sqliteDriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// sqlite takes one writer at a time, queue them in the pool instead of
+	// failing on a busy database
+	sqlDB.SetMaxOpenConns(1)
+	if _, err := sqlDB.ExecContext(ctx, sqliteSchema); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to create sqlite schema: %w", err), sqlDB.Close())
+	}
+
+	leases := NewMemoryDB().IndexerLease
+	db := newSQLiteDB(sqlDB, leases)
+	db.transaction = func(fn func(db *DB) error) error {
+		tx, err := sqlDB.Begin()
+		if err != nil {
+			return err
+		}
+		if err := fn(newSQLiteDB(tx, leases)); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		return tx.Commit()
+	}
+	db.close = sqlDB.Close
+	return db, nil
+}
+
+func newSQLiteDB(db sqlExecutor, leases IndexerLeaseDB) *DB {
+	store := &sqliteStore{db: db}
+	return &DB{
+		GasFee:        store,
+		GasFeeHistory: store,
+		TokenPrice:    store,
+		ApiConsumer:   store,
+		ApiQuota:      store,
+		ChainConfig:   store,
+		TokenConfig:   store,
+		IndexerLease:  leases,
+	}
+}
+
+func bigToText(v *big.Int) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: v.String(), Valid: true}
+}
+
+func textToBig(v sql.NullString) *big.Int {
+	if !v.Valid {
+		return nil
+	}
+	n, _ := new(big.Int).SetString(v.String, 10)
+	return n
+}
+
+// notFound maps an empty result to the error gorm reports for postgres, so
+// callers see the same error whatever the driver.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+func (s *sqliteStore) StoreOrUpdateGasFee(gasFee *GasFee) error {
+	_, err := s.db.ExecContext(context.Background(), `INSERT INTO gas_fee (guid, chain_id, token_name, decimal, predict_fee, base_fee, gas_price, priority_fee, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chain_id) DO UPDATE SET token_name = excluded.token_name, decimal = excluded.decimal, predict_fee = excluded.predict_fee,
+		base_fee = excluded.base_fee, gas_price = excluded.gas_price, priority_fee = excluded.priority_fee, timestamp = excluded.timestamp`,
+		uuid.New().String(), gasFee.ChainId.Int64(), gasFee.TokenName, gasFee.Decimal, bigToText(gasFee.PredictFee),
+		bigToText(gasFee.BaseFee), bigToText(gasFee.GasPrice), bigToText(gasFee.PriorityFee), gasFee.Timestamp)
+	if err != nil {
+		log.Error("store or update gas fee fail", "err", err)
+		return err
+	}
+	return nil
+}
+
+const gasFeeColumns = "guid, chain_id, token_name, decimal, predict_fee, base_fee, gas_price, priority_fee, timestamp"
+
+func scanGasFee(scan func(dest ...any) error) (GasFee, error) {
+	var gasFee GasFee
+	var guid string
+	var chainId int64
+	var predictFee, baseFee, gasPrice, priorityFee sql.NullString
+	if err := scan(&guid, &chainId, &gasFee.TokenName, &gasFee.Decimal, &predictFee, &baseFee, &gasPrice, &priorityFee, &gasFee.Timestamp); err != nil {
+		return gasFee, err
+	}
+	gasFee.GUID, _ = uuid.Parse(guid)
+	gasFee.ChainId = big.NewInt(chainId)
+	gasFee.PredictFee = textToBig(predictFee)
+	gasFee.BaseFee, gasFee.GasPrice, gasFee.PriorityFee = textToBig(baseFee), textToBig(gasPrice), textToBig(priorityFee)
+	return gasFee, nil
+}
+
+func (s *sqliteStore) QueryGasFees(chainId string) (*GasFee, error) {
+	row := s.db.QueryRowContext(context.Background(), "SELECT "+gasFeeColumns+" FROM gas_fee WHERE chain_id = ?", chainId)
+	gasFee, err := scanGasFee(row.Scan)
+	if err != nil {
+		log.Error("get gas fee fail", "err", err)
+		return nil, notFound(err)
+	}
+	return &gasFee, nil
+}
+
+func (s *sqliteStore) QueryGasFeeList() ([]GasFee, error) {
+	rows, err := s.db.QueryContext(context.Background(), "SELECT "+gasFeeColumns+" FROM gas_fee ORDER BY chain_id ASC")
+	if err != nil {
+		log.Error("get gas fee list fail", "err", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var gasFeeList []GasFee
+	for rows.Next() {
+		gasFee, err := scanGasFee(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		gasFeeList = append(gasFeeList, gasFee)
+	}
+	return gasFeeList, rows.Err()
+}
+
+func (s *sqliteStore) StoreGasFeeHistories(histories []GasFeeHistory) error {
+	for _, history := range histories {
+		_, err := s.db.ExecContext(context.Background(), `INSERT INTO gas_fee_history (guid, chain_id, block_number, base_fee, gas_used_ratio,
+			priority_fee_min, priority_fee_p25, priority_fee_p50, priority_fee_p75, priority_fee_max, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (chain_id, block_number) DO NOTHING`,
+			uuid.New().String(), history.ChainId.Int64(), history.BlockNumber.Int64(), bigToText(history.BaseFee), history.GasUsedRatio,
+			bigToText(history.PriorityFeeMin), bigToText(history.PriorityFeeP25), bigToText(history.PriorityFeeP50),
+			bigToText(history.PriorityFeeP75), bigToText(history.PriorityFeeMax), history.Timestamp)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteGasFeeHistoriesBefore(chainId string, blockNumber *big.Int) error {
+	_, err := s.db.ExecContext(context.Background(), "DELETE FROM gas_fee_history WHERE chain_id = ? AND block_number < ?", chainId, blockNumber.Int64())
+	return err
+}
+
+func (s *sqliteStore) QueryGasFeeHistories(chainId string, newestBlock *big.Int, limit int) ([]GasFeeHistory, error) {
+	query := `SELECT guid, chain_id, block_number, base_fee, gas_used_ratio, priority_fee_min, priority_fee_p25,
+		priority_fee_p50, priority_fee_p75, priority_fee_max, timestamp FROM gas_fee_history WHERE chain_id = ?`
+	args := []any{chainId}
+	if newestBlock != nil {
+		query += " AND block_number <= ?"
+		args = append(args, newestBlock.Int64())
+	}
+	// the newest blocks, returned oldest first
+	query = "SELECT * FROM (" + query + " ORDER BY block_number DESC LIMIT ?) ORDER BY block_number ASC"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		log.Error("get gas fee history fail", "err", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var histories []GasFeeHistory
+	for rows.Next() {
+		var history GasFeeHistory
+		var guid string
+		var chainId, blockNumber int64
+		var baseFee, feeMin, feeP25, feeP50, feeP75, feeMax sql.NullString
+		if err := rows.Scan(&guid, &chainId, &blockNumber, &baseFee, &history.GasUsedRatio, &feeMin, &feeP25, &feeP50, &feeP75, &feeMax, &history.Timestamp); err != nil {
+			return nil, err
+		}
+		history.GUID, _ = uuid.Parse(guid)
+		history.ChainId, history.BlockNumber = big.NewInt(chainId), big.NewInt(blockNumber)
+		history.BaseFee = textToBig(baseFee)
+		history.PriorityFeeMin, history.PriorityFeeP25, history.PriorityFeeP50 = textToBig(feeMin), textToBig(feeP25), textToBig(feeP50)
+		history.PriorityFeeP75, history.PriorityFeeMax = textToBig(feeP75), textToBig(feeMax)
+		histories = append(histories, history)
+	}
+	return histories, rows.Err()
+}
+
+func (s *sqliteStore) StoreOrUpdateTokenPrice(tokenPrice *TokenPrice) error {
+	_, err := s.db.ExecContext(context.Background(), `INSERT INTO token_price (guid, token_name, token_symbol, skeye_symbol, quote_currency, decimal, market_price, derivation_path, confidence,
+			price_state, price_state_reason, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (token_symbol, quote_currency) DO UPDATE SET skeye_symbol = excluded.skeye_symbol, market_price = excluded.market_price,
+			derivation_path = excluded.derivation_path, confidence = excluded.confidence,
+			price_state = excluded.price_state, price_state_reason = excluded.price_state_reason, timestamp = excluded.timestamp`,
+		uuid.New().String(), tokenPrice.TokenName, tokenPrice.TokenSymbol, tokenPrice.SkeyeSymbol, tokenPrice.QuoteCurrency, tokenPrice.Decimal,
+		tokenPrice.MarketPriceString(), tokenPrice.DerivationPath, tokenPrice.Confidence,
+		tokenPrice.PriceState, tokenPrice.PriceStateReason, tokenPrice.Timestamp)
+	if err != nil {
+		log.Error("store or update token price fail", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sqliteStore) UpdateTokenPriceState(symbol string, quoteCurrency string, state string, reason string) error {
+	_, err := s.db.ExecContext(context.Background(), `UPDATE token_price SET price_state = ?, price_state_reason = ?
+		WHERE token_symbol = ? AND quote_currency = ?`, state, reason, symbol, quoteCurrency)
+	if err != nil {
+		log.Error("update token price state fail", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sqliteStore) QueryTokenPrices(symbol string, quoteCurrency string) (*TokenPrice, error) {
+	var tokenPrice TokenPrice
+	var guid, marketPrice string
+	err := s.db.QueryRowContext(context.Background(), `SELECT guid, token_name, token_symbol, skeye_symbol, quote_currency, decimal, market_price, derivation_path, confidence,
+			price_state, price_state_reason, timestamp
+		FROM token_price WHERE token_symbol = ? AND quote_currency = ?`, symbol, quoteCurrency).
+		Scan(&guid, &tokenPrice.TokenName, &tokenPrice.TokenSymbol, &tokenPrice.SkeyeSymbol, &tokenPrice.QuoteCurrency, &tokenPrice.Decimal, &marketPrice,
+			&tokenPrice.DerivationPath, &tokenPrice.Confidence, &tokenPrice.PriceState, &tokenPrice.PriceStateReason, &tokenPrice.Timestamp)
+	if err != nil {
+		log.Error("get token price fail", "err", err)
+		return nil, notFound(err)
+	}
+	if tokenPrice.MarketPrice, err = bigint.ParseDecimal(marketPrice); err != nil {
+		return nil, err
+	}
+	tokenPrice.GUID, _ = uuid.Parse(guid)
+	return &tokenPrice, nil
+}
+
+func (s *sqliteStore) QueryApiConsumer(token string) (*ApiConsumer, error) {
+	var apiConsumer ApiConsumer
+	var guid string
+	err := s.db.QueryRowContext(context.Background(), "SELECT guid, name, token, enabled, expire_at, timestamp FROM api_consumer WHERE token = ?", token).
+		Scan(&guid, &apiConsumer.Name, &apiConsumer.Token, &apiConsumer.Enabled, &apiConsumer.ExpireAt, &apiConsumer.Timestamp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Error("get api consumer fail", "err", err)
+		return nil, err
+	}
+	apiConsumer.GUID, _ = uuid.Parse(guid)
+	return &apiConsumer, nil
+}
+
+func (s *sqliteStore) StoreApiConsumerAudit(audit *ApiConsumerAudit) error {
+	_, err := s.db.ExecContext(context.Background(), "INSERT INTO api_consumer_audit (guid, consumer_name, method, peer_addr, timestamp) VALUES (?, ?, ?, ?, ?)",
+		uuid.New().String(), audit.ConsumerName, audit.Method, audit.PeerAddr, audit.Timestamp)
+	return err
+}
+
+func (s *sqliteStore) IncreaseApiQuota(quotaKey string, day string, count uint64) (uint64, error) {
+	var requestCount uint64
+	err := s.db.QueryRowContext(context.Background(), `INSERT INTO api_quota (guid, quota_key, day, request_count, timestamp) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (quota_key, day) DO UPDATE SET request_count = api_quota.request_count + excluded.request_count, timestamp = excluded.timestamp
+		RETURNING request_count`, uuid.New().String(), quotaKey, day, count, time.Now().Unix()).Scan(&requestCount)
+	if err != nil {
+		log.Error("increase api quota fail", "quotaKey", quotaKey, "err", err)
+		return 0, err
+	}
+	return requestCount, nil
+}
+
+func (s *sqliteStore) QueryApiQuota(quotaKey string, day string) (*ApiQuota, error) {
+	var apiQuota ApiQuota
+	var guid string
+	err := s.db.QueryRowContext(context.Background(), "SELECT guid, quota_key, day, request_count, timestamp FROM api_quota WHERE quota_key = ? AND day = ?", quotaKey, day).
+		Scan(&guid, &apiQuota.QuotaKey, &apiQuota.Day, &apiQuota.RequestCount, &apiQuota.Timestamp)
+	if err != nil {
+		log.Error("get api quota fail", "err", err)
+		return nil, notFound(err)
+	}
+	apiQuota.GUID, _ = uuid.Parse(guid)
+	return &apiQuota, nil
+}
+
+func (s *sqliteStore) StoreOrUpdateChainConfig(chainConfig *ChainConfig) error {
+	rpcUrls, err := json.Marshal(chainConfig.RpcUrls)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(context.Background(), `INSERT INTO chain_config (guid, chain_id, chain_name, rpc_urls, native_token, decimal, enabled, back_offset, loop_interval, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (chain_id) DO UPDATE SET chain_name = excluded.chain_name, rpc_urls = excluded.rpc_urls, native_token = excluded.native_token,
+		decimal = excluded.decimal, enabled = excluded.enabled, back_offset = excluded.back_offset, loop_interval = excluded.loop_interval, timestamp = excluded.timestamp`,
+		uuid.New().String(), chainConfig.ChainId.Int64(), chainConfig.ChainName, string(rpcUrls), chainConfig.NativeToken, chainConfig.Decimal,
+		chainConfig.Enabled, chainConfig.BackOffset, chainConfig.LoopInterval, chainConfig.Timestamp)
+	if err != nil {
+		log.Error("store or update chain config fail", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteChainConfig(chainId *big.Int) (bool, error) {
+	result, err := s.db.ExecContext(context.Background(), "DELETE FROM chain_config WHERE chain_id = ?", chainId.Int64())
+	if err != nil {
+		log.Error("delete chain config fail", "err", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (s *sqliteStore) QueryChainConfigList() ([]ChainConfig, error) {
+	rows, err := s.db.QueryContext(context.Background(), `SELECT guid, chain_id, chain_name, rpc_urls, native_token, decimal, enabled, back_offset, loop_interval, timestamp
+		FROM chain_config ORDER BY chain_id ASC`)
+	if err != nil {
+		log.Error("get chain config list fail", "err", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var chainConfigList []ChainConfig
+	for rows.Next() {
+		var chainConfig ChainConfig
+		var guid, rpcUrls string
+		var chainId int64
+		if err := rows.Scan(&guid, &chainId, &chainConfig.ChainName, &rpcUrls, &chainConfig.NativeToken, &chainConfig.Decimal,
+			&chainConfig.Enabled, &chainConfig.BackOffset, &chainConfig.LoopInterval, &chainConfig.Timestamp); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(rpcUrls), &chainConfig.RpcUrls); err != nil {
+			return nil, fmt.Errorf("invalid rpc_urls of chain %d: %w", chainId, err)
+		}
+		chainConfig.GUID, _ = uuid.Parse(guid)
+		chainConfig.ChainId = big.NewInt(chainId)
+		chainConfigList = append(chainConfigList, chainConfig)
+	}
+	return chainConfigList, rows.Err()
+}
+
+func (s *sqliteStore) StoreOrUpdateTokenConfig(tokenConfig *TokenConfig) error {
+	aliases, err := json.Marshal(tokenConfig.Aliases)
+	if err != nil {
+		return err
+	}
+	addresses, err := json.Marshal(tokenConfig.Addresses)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(context.Background(), `INSERT INTO token_config (guid, symbol, skeye_symbol, aliases, addresses, decimal, enabled, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (symbol) DO UPDATE SET skeye_symbol = excluded.skeye_symbol, aliases = excluded.aliases, addresses = excluded.addresses,
+		decimal = excluded.decimal, enabled = excluded.enabled, timestamp = excluded.timestamp`,
+		uuid.New().String(), tokenConfig.Symbol, tokenConfig.SkeyeSymbol, string(aliases), string(addresses), tokenConfig.Decimal, tokenConfig.Enabled, tokenConfig.Timestamp)
+	if err != nil {
+		log.Error("store or update token config fail", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (s *sqliteStore) DeleteTokenConfig(symbol string) (bool, error) {
+	result, err := s.db.ExecContext(context.Background(), "DELETE FROM token_config WHERE symbol = ?", symbol)
+	if err != nil {
+		log.Error("delete token config fail", "err", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (s *sqliteStore) QueryTokenConfigList() ([]TokenConfig, error) {
+	rows, err := s.db.QueryContext(context.Background(), `SELECT guid, symbol, skeye_symbol, aliases, addresses, decimal, enabled, timestamp
+		FROM token_config ORDER BY symbol ASC`)
+	if err != nil {
+		log.Error("get token config list fail", "err", err)
+		return nil, err
+	}
+	defer rows.Close()
+	var tokenConfigList []TokenConfig
+	for rows.Next() {
+		var tokenConfig TokenConfig
+		var guid, aliases, addresses string
+		if err := rows.Scan(&guid, &tokenConfig.Symbol, &tokenConfig.SkeyeSymbol, &aliases, &addresses, &tokenConfig.Decimal,
+			&tokenConfig.Enabled, &tokenConfig.Timestamp); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(aliases), &tokenConfig.Aliases); err != nil {
+			return nil, fmt.Errorf("invalid aliases of token %s: %w", tokenConfig.Symbol, err)
+		}
+		if err := json.Unmarshal([]byte(addresses), &tokenConfig.Addresses); err != nil {
+			return nil, fmt.Errorf("invalid addresses of token %s: %w", tokenConfig.Symbol, err)
+		}
+		tokenConfig.GUID, _ = uuid.Parse(guid)
+		tokenConfigList = append(tokenConfigList, tokenConfig)
+	}
+	return tokenConfigList, rows.Err()
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type TokenConfig struct {
+	GUID        uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
+	Symbol      string    `json:"symbol"`
+	SkeyeSymbol string    `json:"skeye_symbol"`
+	// Aliases are other names resolving to Symbol, Addresses its token contracts by chain id
+	Aliases   []string          `json:"aliases" gorm:"serializer:json"`
+	Addresses map[uint64]string `json:"addresses" gorm:"serializer:json"`
+	Decimal   uint8             `json:"decimal"`
+	Enabled   bool              `json:"enabled"`
+	Timestamp uint64            `json:"timestamp"`
+}
+
+func (TokenConfig) TableName() string {
+	return "token_config"
+}
+
+type tokenConfigDB struct {
+	gorm *gorm.DB
+}
+
+type TokenConfigDB interface {
+	TokenConfigView
+	StoreOrUpdateTokenConfig(tokenConfig *TokenConfig) error
+	DeleteTokenConfig(symbol string) (bool, error)
+}
+
+type TokenConfigView interface {
+	QueryTokenConfigList() ([]TokenConfig, error)
+}
+
+func NewTokenConfigDB(db *gorm.DB) TokenConfigDB {
+	return &tokenConfigDB{gorm: db}
+}
+
+func (db *tokenConfigDB) StoreOrUpdateTokenConfig(tokenConfig *TokenConfig) error {
+	result := db.gorm.Table("token_config").Omit("guid").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}},
+		DoUpdates: clause.AssignmentColumns([]string{"skeye_symbol", "aliases", "addresses", "decimal", "enabled", "timestamp"}),
+	}).Create(tokenConfig)
+	if result.Error != nil {
+		log.Error("store or update token config fail", "err", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// DeleteTokenConfig removes the symbol and reports whether it existed.
+func (db *tokenConfigDB) DeleteTokenConfig(symbol string) (bool, error) {
+	result := db.gorm.Table("token_config").Where("symbol = ?", symbol).Delete(&TokenConfig{})
+	if result.Error != nil {
+		log.Error("delete token config fail", "err", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (db *tokenConfigDB) QueryTokenConfigList() ([]TokenConfig, error) {
+	var tokenConfigList []TokenConfig
+	err := db.gorm.Table("token_config").Order("symbol asc").Find(&tokenConfigList).Error
+	if err != nil {
+		log.Error("get token config list fail", "err", err)
+		return nil, err
+	}
+	return tokenConfigList, nil
+}
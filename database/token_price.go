@@ -1,26 +1,54 @@
 package database
 
 import (
+	"math/big"
+
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/common/bigint"
+	"github.com/cpchain-network/gas-oracle/database/utils/serializers"
+)
+
+const (
+	PriceStateOk = "ok"
+	// PriceStateFrozen keeps the last good price while new samples are rejected
+	PriceStateFrozen = "frozen"
 )
 
 type TokenPrice struct {
 	GUID        uuid.UUID `json:"guid" gorm:"primaryKey;DEFAULT replace(uuid_generate_v4()::text,'-','');serializer:uuid"`
 	TokenName   string    `json:"token_name"`
 	TokenSymbol string    `json:"token_symbol"`
-	Decimal     uint8     `json:"decimal"`
-	MarketPrice string    `json:"market_price"`
-	Timestamp   uint64    `json:"timestamp"`
+	// SkeyeSymbol is the symbol the price was queried from skyeye with
+	SkeyeSymbol   string   `json:"skeye_symbol"`
+	QuoteCurrency string   `json:"quote_currency"`
+	Decimal       uint8    `json:"decimal"`
+	MarketPrice   *big.Rat `json:"market_price" gorm:"serializer:decimal"`
+	// DerivationPath lists the rates multiplied into the price, empty for a direct feed
+	DerivationPath string  `json:"derivation_path"`
+	Confidence     float64 `json:"confidence"`
+	// PriceState is PriceStateOk or PriceStateFrozen, with the reason of the freeze
+	PriceState       string `json:"price_state"`
+	PriceStateReason string `json:"price_state_reason"`
+	Timestamp        uint64 `json:"timestamp"`
 }
 
 func (TokenPrice) TableName() string {
 	return "token_price"
 }
 
+// MarketPriceString formats the price the way the database stores it.
+func (p *TokenPrice) MarketPriceString() string {
+	if p.MarketPrice == nil {
+		return ""
+	}
+	return bigint.FormatDecimal(p.MarketPrice, serializers.DecimalScale)
+}
+
 type tokenPriceDB struct {
 	gorm *gorm.DB
 }
@@ -28,38 +56,44 @@ type tokenPriceDB struct {
 type TokenPriceDB interface {
 	TokenPriceView
 	StoreOrUpdateTokenPrice(msgHash *TokenPrice) error
+	UpdateTokenPriceState(symbol string, quoteCurrency string, state string, reason string) error
 }
 
 type TokenPriceView interface {
-	QueryTokenPrices(symbol string) (*TokenPrice, error)
+	QueryTokenPrices(symbol string, quoteCurrency string) (*TokenPrice, error)
 }
 
 func NewTokenPriceDB(db *gorm.DB) TokenPriceDB {
 	return &tokenPriceDB{gorm: db}
 }
 
+// StoreOrUpdateTokenPrice upserts the single price row of the symbol in its quote currency.
 func (db *tokenPriceDB) StoreOrUpdateTokenPrice(tokenPrice *TokenPrice) error {
-	var tokenPriceRecord TokenPrice
-	err := db.gorm.Table("token_price").Where("token_symbol = ?", tokenPrice.TokenSymbol).Take(&tokenPriceRecord).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			result := db.gorm.Table("token_price").Omit("guid").Create(tokenPrice)
-			return result.Error
-		}
+	result := db.gorm.Table("token_price").Omit("guid").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token_symbol"}, {Name: "quote_currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"skeye_symbol", "market_price", "derivation_path", "confidence", "price_state", "price_state_reason", "timestamp"}),
+	}).Create(tokenPrice)
+	if result.Error != nil {
+		log.Error("store or update token price fail", "err", result.Error)
+		return result.Error
 	}
-	tokenPriceRecord.MarketPrice = tokenPrice.MarketPrice
-	tokenPriceRecord.Timestamp = tokenPrice.Timestamp
-	err = db.gorm.Table("token_price").Save(tokenPriceRecord).Error
-	if err != nil {
-		log.Error("save token price record fail", "err", err)
-		return err
+	return nil
+}
+
+// UpdateTokenPriceState changes the state of a stored price, keeping the price.
+func (db *tokenPriceDB) UpdateTokenPriceState(symbol string, quoteCurrency string, state string, reason string) error {
+	result := db.gorm.Table("token_price").Where("token_symbol = ? AND quote_currency = ?", symbol, quoteCurrency).
+		Updates(map[string]interface{}{"price_state": state, "price_state_reason": reason})
+	if result.Error != nil {
+		log.Error("update token price state fail", "err", result.Error)
+		return result.Error
 	}
 	return nil
 }
 
-func (db *tokenPriceDB) QueryTokenPrices(symbol string) (*TokenPrice, error) {
+func (db *tokenPriceDB) QueryTokenPrices(symbol string, quoteCurrency string) (*TokenPrice, error) {
 	var tokenPrice TokenPrice
-	err := db.gorm.Table("token_price").Where("token_symbol = ?", symbol).Take(&tokenPrice).Error
+	err := db.gorm.Table("token_price").Where("token_symbol = ? AND quote_currency = ?", symbol, quoteCurrency).Take(&tokenPrice).Error
 	if err != nil {
 		log.Error("get token price fail", "err", err)
 		return nil, err
@@ -0,0 +1,68 @@
+package serializers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/jackc/pgtype"
+	"gorm.io/gorm/schema"
+
+	"github.com/cpchain-network/gas-oracle/common/bigint"
+)
+
+// DecimalScale is the number of fractional digits of the NUMERIC columns the
+// decimal serializer writes to.
+const DecimalScale = 18
+
+type DecimalSerializer struct{}
+
+func init() {
+	schema.RegisterSerializer("decimal", DecimalSerializer{})
+}
+
+func (DecimalSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	if dbValue == nil {
+		return nil
+	} else if field.FieldType != reflect.TypeOf((*big.Rat)(nil)) {
+		return fmt.Errorf("can only deserialize into a *big.Rat: %T", field.FieldType)
+	}
+
+	numeric := new(pgtype.Numeric)
+	err := numeric.Scan(dbValue)
+	if err != nil {
+		return err
+	}
+	if numeric.NaN || numeric.InfinityModifier != pgtype.None {
+		return fmt.Errorf("deserialized number is not finite: %v", dbValue)
+	}
+
+	rat := new(big.Rat).SetInt(numeric.Int)
+	factor := new(big.Int).Exp(big10, big.NewInt(int64(abs(numeric.Exp))), nil)
+	if numeric.Exp > 0 {
+		rat.Mul(rat, new(big.Rat).SetInt(factor))
+	} else if numeric.Exp < 0 {
+		rat.Quo(rat, new(big.Rat).SetInt(factor))
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(rat))
+	return nil
+}
+
+func (DecimalSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if fieldValue == nil || (field.FieldType.Kind() == reflect.Pointer && reflect.ValueOf(fieldValue).IsNil()) {
+		return nil, nil
+	} else if field.FieldType != reflect.TypeOf((*big.Rat)(nil)) {
+		return nil, fmt.Errorf("can only serialize a *big.Rat: %T", field.FieldType)
+	}
+
+	return bigint.FormatDecimal(fieldValue.(*big.Rat), DecimalScale), nil
+}
+
+func abs(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,244 @@
+package election
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/common/tasks"
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// replicaKeyPrefix marks the heartbeat lease every replica keeps so the live
+// replicas can be counted when balancing.
+const replicaKeyPrefix = "replica:"
+
+// Participant is what an Elector runs leases for.
+type Participant interface {
+	// LeaseKeys returns the leases to compete for. It is called on every round
+	// so the keys may change over time.
+	LeaseKeys() []string
+	// Acquired starts the work guarded by key.
+	Acquired(key string) error
+	// Lost stops the work guarded by key.
+	Lost(key string)
+}
+
+// Elector keeps the leases of a Participant in the indexer_lease table, so
+// that across replicas each key is worked on by exactly one of them and a
+// standby takes over once the holder stops renewing.
+type Elector struct {
+	db          *database.DB
+	conf        config.LeaderElection
+	holder      string
+	participant Participant
+
+	mu       sync.Mutex
+	held     map[string]time.Time // key -> last successful renewal
+	replicas int64
+	// heldMu guards held on its own so Holds never waits on a round, which
+	// calls into the participant
+	heldMu sync.RWMutex
+	now    func() time.Time
+
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	stopped        atomic.Bool
+}
+
+func NewElector(db *database.DB, conf config.LeaderElection, participant Participant, shutdown context.CancelCauseFunc) (*Elector, error) {
+	holder, err := newHolderId()
+	if err != nil {
+		return nil, err
+	}
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Elector{
+		db:             db,
+		conf:           conf,
+		holder:         holder,
+		participant:    participant,
+		held:           make(map[string]time.Time),
+		replicas:       1,
+		now:            time.Now,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in leader elector: %w", err))
+		}},
+	}, nil
+}
+
+// Holder is the id this replica holds its leases under.
+func (e *Elector) Holder() string {
+	return e.holder
+}
+
+func (e *Elector) Start(ctx context.Context) error {
+	log.Info("leader election started", "holder", e.holder, "leaseTTL", e.conf.LeaseTTL, "balance", e.conf.Balance)
+	e.tasks.Go(func() error {
+		ticker := time.NewTicker(e.conf.RenewInterval)
+		defer ticker.Stop()
+		for {
+			e.round()
+			select {
+			case <-e.resourceCtx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+	return nil
+}
+
+// Stop hands back every lease so standbys can take over right away instead
+// of waiting for them to expire.
+func (e *Elector) Stop(ctx context.Context) error {
+	e.resourceCancel()
+	err := e.tasks.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for key := range e.held {
+		e.lose(key, true)
+	}
+	if err := e.db.IndexerLease.ReleaseLease(replicaKeyPrefix+e.holder, e.holder); err != nil {
+		log.Warn("release replica lease fail", "err", err)
+	}
+	e.stopped.Store(true)
+	log.Info("leader election stopped", "holder", e.holder)
+	return err
+}
+
+func (e *Elector) Stopped() bool {
+	return e.stopped.Load()
+}
+
+// Holds reports whether this replica currently holds key.
+func (e *Elector) Holds(key string) bool {
+	e.heldMu.RLock()
+	defer e.heldMu.RUnlock()
+	_, ok := e.held[key]
+	return ok
+}
+
+func (e *Elector) setHeld(key string, renewedAt time.Time) {
+	e.heldMu.Lock()
+	defer e.heldMu.Unlock()
+	e.held[key] = renewedAt
+}
+
+func (e *Elector) round() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := e.db.IndexerLease.AcquireLease(replicaKeyPrefix+e.holder, e.holder, e.conf.LeaseTTL); err != nil {
+		log.Warn("renew replica lease fail", "err", err)
+	}
+	if e.conf.Balance {
+		if replicas, err := e.db.IndexerLease.CountLiveLeases(replicaKeyPrefix); err == nil && replicas > 0 {
+			e.replicas = replicas
+		}
+	}
+
+	keys := e.participant.LeaseKeys()
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+	for key := range e.held {
+		if !wanted[key] {
+			e.lose(key, true)
+		}
+	}
+
+	now := e.now()
+	share := e.fairShare(len(keys))
+	for _, key := range keys {
+		renewedAt, holding := e.held[key]
+		if !holding && len(e.held) >= share {
+			continue
+		}
+		ok, err := e.db.IndexerLease.AcquireLease(key, e.holder, e.conf.LeaseTTL)
+		switch {
+		case err != nil:
+			// keep working through a database hiccup, but stop one renew
+			// interval before the lease expires: the next round may come too
+			// late to stop before a standby takes the lease over
+			if holding && now.Sub(renewedAt) >= e.conf.LeaseTTL-e.conf.RenewInterval {
+				log.Warn("lease renewal failing until close to expiry, giving it up", "key", key)
+				e.lose(key, false)
+			}
+		case ok && holding:
+			e.setHeld(key, now)
+		case ok:
+			if err := e.participant.Acquired(key); err != nil {
+				log.Error("start leased work fail, handing the lease back", "key", key, "err", err)
+				if err := e.db.IndexerLease.ReleaseLease(key, e.holder); err != nil {
+					log.Warn("release lease fail", "key", key, "err", err)
+				}
+				continue
+			}
+			log.Info("lease acquired", "key", key, "holder", e.holder)
+			e.setHeld(key, now)
+		case holding:
+			log.Warn("lease taken over by another replica", "key", key)
+			e.lose(key, false)
+		}
+	}
+
+	// hand back the surplus so replicas that joined later get their share
+	if surplus := len(e.held) - share; surplus > 0 {
+		heldKeys := make([]string, 0, len(e.held))
+		for key := range e.held {
+			heldKeys = append(heldKeys, key)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(heldKeys)))
+		for _, key := range heldKeys[:surplus] {
+			log.Info("handing lease over to balance replicas", "key", key, "replicas", e.replicas)
+			e.lose(key, true)
+		}
+	}
+}
+
+// fairShare is how many of keys this replica may hold.
+func (e *Elector) fairShare(keys int) int {
+	if !e.conf.Balance || e.replicas <= 1 {
+		return keys
+	}
+	return (keys + int(e.replicas) - 1) / int(e.replicas)
+}
+
+func (e *Elector) lose(key string, release bool) {
+	e.heldMu.Lock()
+	delete(e.held, key)
+	e.heldMu.Unlock()
+	e.participant.Lost(key)
+	if release {
+		if err := e.db.IndexerLease.ReleaseLease(key, e.holder); err != nil {
+			log.Warn("release lease fail", "key", key, "err", err)
+		}
+	}
+	log.Info("lease released", "key", key, "holder", e.holder)
+}
+
+func newHolderId() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate holder id: %w", err)
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix)), nil
+}
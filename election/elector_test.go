@@ -0,0 +1,188 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// memoryLeases mimics the indexer_lease table on a fake clock.
+type memoryLeases struct {
+	mu     sync.Mutex
+	now    time.Time
+	leases map[string]database.IndexerLease
+	// down fails every lease renewal, as during a database outage
+	down bool
+}
+
+func (m *memoryLeases) AcquireLease(leaseKey string, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return false, errors.New("connection refused")
+	}
+	lease, ok := m.leases[leaseKey]
+	if ok && lease.Holder != holder && lease.ExpireAt >= m.now.UnixMilli() {
+		return false, nil
+	}
+	m.leases[leaseKey] = database.IndexerLease{LeaseKey: leaseKey, Holder: holder, ExpireAt: m.now.Add(ttl).UnixMilli()}
+	return true, nil
+}
+
+func (m *memoryLeases) ReleaseLease(leaseKey string, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases[leaseKey].Holder == holder {
+		delete(m.leases, leaseKey)
+	}
+	return nil
+}
+
+func (m *memoryLeases) CountLiveLeases(keyPrefix string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for key, lease := range m.leases {
+		if strings.HasPrefix(key, keyPrefix) && lease.ExpireAt >= m.now.UnixMilli() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryLeases) clock() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+func (m *memoryLeases) setDown(down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = down
+}
+
+func (m *memoryLeases) advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+type recordingParticipant struct {
+	keys   []string
+	active map[string]bool
+}
+
+func (p *recordingParticipant) LeaseKeys() []string { return p.keys }
+
+func (p *recordingParticipant) Acquired(key string) error {
+	p.active[key] = true
+	return nil
+}
+
+func (p *recordingParticipant) Lost(key string) {
+	delete(p.active, key)
+}
+
+func newTestElector(t *testing.T, leases *memoryLeases, balance bool, keys []string) (*Elector, *recordingParticipant) {
+	participant := &recordingParticipant{keys: keys, active: make(map[string]bool)}
+	conf := config.LeaderElection{Enable: true, LeaseTTL: 15 * time.Second, RenewInterval: 5 * time.Second, Balance: balance}
+	elector, err := NewElector(&database.DB{IndexerLease: leases}, conf, participant, func(error) {})
+	require.NoError(t, err)
+	elector.now = leases.clock
+	return elector, participant
+}
+
+func TestStandbyTakesOverExpiredLeases(t *testing.T) {
+	leases := &memoryLeases{now: time.Unix(1_700_000_000, 0), leases: make(map[string]database.IndexerLease)}
+	keys := []string{"chain:1", "chain:10", "worker"}
+	leader, leaderWork := newTestElector(t, leases, false, keys)
+	standby, standbyWork := newTestElector(t, leases, false, keys)
+
+	leader.round()
+	standby.round()
+	require.Len(t, leaderWork.active, 3)
+	require.Empty(t, standbyWork.active)
+
+	// the leader dies and stops renewing
+	leases.advance(10 * time.Second)
+	standby.round()
+	require.Empty(t, standbyWork.active, "leases must not be taken before they expire")
+
+	leases.advance(10 * time.Second)
+	standby.round()
+	require.Len(t, standbyWork.active, 3)
+	require.True(t, standby.Holds("worker"))
+}
+
+func TestStopHandsLeasesOver(t *testing.T) {
+	leases := &memoryLeases{now: time.Unix(1_700_000_000, 0), leases: make(map[string]database.IndexerLease)}
+	keys := []string{"chain:1", "worker"}
+	leader, leaderWork := newTestElector(t, leases, false, keys)
+	standby, standbyWork := newTestElector(t, leases, false, keys)
+
+	leader.round()
+	require.NoError(t, leader.Stop(context.Background()))
+	require.Empty(t, leaderWork.active)
+
+	standby.round()
+	require.Len(t, standbyWork.active, 2)
+}
+
+func TestBalanceSpreadsLeases(t *testing.T) {
+	leases := &memoryLeases{now: time.Unix(1_700_000_000, 0), leases: make(map[string]database.IndexerLease)}
+	keys := []string{"chain:1", "chain:10", "chain:56", "worker"}
+	first, firstWork := newTestElector(t, leases, true, keys)
+	second, secondWork := newTestElector(t, leases, true, keys)
+
+	first.round()
+	require.Len(t, firstWork.active, 4)
+
+	// the second replica joins: the first hands over its surplus, which the
+	// second picks up on its next round
+	second.round()
+	first.round()
+	second.round()
+	require.Len(t, firstWork.active, 2)
+	require.Len(t, secondWork.active, 2)
+	for _, key := range keys {
+		require.True(t, firstWork.active[key] != secondWork.active[key], "%s must run on exactly one replica", key)
+	}
+}
+
+func TestLeaderStopsBeforeLeaseExpiresDuringOutage(t *testing.T) {
+	leases := &memoryLeases{now: time.Unix(1_700_000_000, 0), leases: make(map[string]database.IndexerLease)}
+	keys := []string{"chain:1", "worker"}
+	leader, leaderWork := newTestElector(t, leases, false, keys)
+	standby, standbyWork := newTestElector(t, leases, false, keys)
+
+	leader.round()
+	require.Len(t, leaderWork.active, 2)
+
+	// the leader loses the database while the standby still reaches it
+	leases.setDown(true)
+	leases.advance(5 * time.Second)
+	leader.round()
+	require.Len(t, leaderWork.active, 2, "a short outage keeps the work running")
+
+	leases.advance(5 * time.Second)
+	leader.round()
+	require.Empty(t, leaderWork.active, "the work stops one renew interval before the lease expires")
+	require.False(t, leader.Holds("worker"))
+
+	leases.setDown(false)
+	leases.advance(6 * time.Second)
+	standby.round()
+	require.Len(t, standbyWork.active, 2)
+	for _, key := range keys {
+		require.False(t, leaderWork.active[key] && standbyWork.active[key], "%s must never run twice", key)
+	}
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"github.com/ethereum/go-ethereum/event"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+type GasFeeEvent struct {
+	GasFee *database.GasFee
+}
+
+type TokenPriceEvent struct {
+	TokenPrice *database.TokenPrice
+}
+
+// Bus delivers freshly stored gas fees and token prices to services running
+// in the same process. A nil Bus is valid and drops every event.
+//
+// Sends block until every subscriber has received the event, so subscribers
+// should read from a buffered channel and not do slow work inline.
+type Bus struct {
+	gasFeeFeed     event.Feed
+	tokenPriceFeed event.Feed
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) PublishGasFee(gasFee *database.GasFee) {
+	if b == nil {
+		return
+	}
+	b.gasFeeFeed.Send(GasFeeEvent{GasFee: gasFee})
+}
+
+func (b *Bus) PublishTokenPrice(tokenPrice *database.TokenPrice) {
+	if b == nil {
+		return
+	}
+	b.tokenPriceFeed.Send(TokenPriceEvent{TokenPrice: tokenPrice})
+}
+
+func (b *Bus) SubscribeGasFee(ch chan<- GasFeeEvent) event.Subscription {
+	return b.gasFeeFeed.Subscribe(ch)
+}
+
+func (b *Bus) SubscribeTokenPrice(ch chan<- TokenPriceEvent) event.Subscription {
+	return b.tokenPriceFeed.Subscribe(ch)
+}
@@ -4,34 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/cpchain-network/gas-oracle/common/symbols"
 	"github.com/cpchain-network/gas-oracle/config"
 	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/election"
+	"github.com/cpchain-network/gas-oracle/event"
 	"github.com/cpchain-network/gas-oracle/synchronizer"
 	"github.com/cpchain-network/gas-oracle/synchronizer/node"
 	"github.com/cpchain-network/gas-oracle/worker"
 )
 
 type GasOracle struct {
+	cfg          *config.Config
 	db           *database.DB
+	sharedDB     bool
+	bus          *event.Bus
 	ethClient    map[uint64]node.EthClient
 	synchronizer map[uint64]*synchronizer.OracleSynchronizer
 	workerHandle *worker.WorkerHandle
+	elector      *election.Elector
 	symbolList   []string
 	shutdown     context.CancelCauseFunc
 	stopped      atomic.Bool
 	backOffset   uint64
 	loopInternal time.Duration
 	chainIdList  []uint64
+	rpcs         map[uint64]config.RPC
+	// symbolMap resolves the configured token names to symbols
+	symbolMap *symbols.Map
+
+	// mu guards the per chain state against a config reload
+	mu         sync.Mutex
+	configPath string
+	// reloadMu serializes reloads, which stop and start chains outside mu
+	reloadMu     sync.Mutex
+	reloadCancel context.CancelFunc
+	reloadDone   chan struct{}
+	// clientMu guards ethClient for the worker, which must not wait on mu
+	clientMu sync.RWMutex
 }
 
 func NewGasOracle(ctx context.Context, cfg *config.Config, shutdown context.CancelCauseFunc) (*GasOracle, error) {
+	return newGasOracle(ctx, cfg, nil, nil, shutdown)
+}
+
+// NewGasOracleFromDB builds a gas oracle on a database handle shared with other
+// services in the process. The handle is left open on Stop, and stored gas fees
+// and token prices are published on bus.
+func NewGasOracleFromDB(ctx context.Context, cfg *config.Config, db *database.DB, bus *event.Bus, shutdown context.CancelCauseFunc) (*GasOracle, error) {
+	return newGasOracle(ctx, cfg, db, bus, shutdown)
+}
+
+func newGasOracle(ctx context.Context, cfg *config.Config, db *database.DB, bus *event.Bus, shutdown context.CancelCauseFunc) (*GasOracle, error) {
 	log.Info("new gas oracle start️ 🕖")
 	out := &GasOracle{
+		db:           db,
+		sharedDB:     db != nil,
+		bus:          bus,
 		loopInternal: cfg.LoopInternal,
 		backOffset:   cfg.BackOffset,
 		shutdown:     shutdown,
@@ -44,6 +80,23 @@ func NewGasOracle(ctx context.Context, cfg *config.Config, shutdown context.Canc
 }
 
 func (as *GasOracle) Start(ctx context.Context) error {
+	if as.elector != nil {
+		// the elector starts the synchronizers and the worker this replica holds leases for
+		if err := as.elector.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start leader election: %w", err)
+		}
+	} else if err := as.startAll(); err != nil {
+		return err
+	}
+
+	if as.configPath != "" {
+		as.watchConfig(as.configPath)
+	}
+
+	return nil
+}
+
+func (as *GasOracle) startAll() error {
 	for i := range as.chainIdList {
 		log.Info("starting sync", "chainId", as.chainIdList[i])
 		realChainId := as.chainIdList[i]
@@ -57,12 +110,23 @@ func (as *GasOracle) Start(ctx context.Context) error {
 		log.Error("start work handle fail", "err", err)
 		return err
 	}
-
 	return nil
 }
 
 func (as *GasOracle) Stop(ctx context.Context) error {
+	if as.reloadCancel != nil {
+		as.reloadCancel()
+		<-as.reloadDone
+	}
 	var result error
+	if as.elector != nil {
+		if err := as.elector.Stop(ctx); err != nil {
+			result = fmt.Errorf("failed to stop leader election: %w", err)
+		}
+	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
 	for i := range as.chainIdList {
 		if as.synchronizer[as.chainIdList[i]] != nil {
 			if err := as.synchronizer[as.chainIdList[i]].Stop(ctx); err != nil {
@@ -74,16 +138,17 @@ func (as *GasOracle) Stop(ctx context.Context) error {
 		}
 	}
 
-	if as.db != nil {
-		if err := as.db.Close(); err != nil {
-			result = errors.Join(result, fmt.Errorf("failed to close DB: %w", err))
+	if as.workerHandle != nil {
+		if err := as.workerHandle.Close(); err != nil {
+			log.Error("close work handle fail", "err", err)
+			result = errors.Join(result, fmt.Errorf("failed to close work handle: %w", err))
 		}
 	}
 
-	err := as.workerHandle.Close()
-	if err != nil {
-		log.Error("close work handle fail", "err", err)
-		return err
+	if as.db != nil && !as.sharedDB {
+		if err := as.db.Close(); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to close DB: %w", err))
+		}
 	}
 
 	as.stopped.Store(true)
@@ -98,12 +163,21 @@ func (as *GasOracle) Stopped() bool {
 }
 
 func (as *GasOracle) initFromConfig(ctx context.Context, cfg *config.Config) error {
-	if err := as.initRPCClients(ctx, cfg); err != nil {
-		return fmt.Errorf("failed to start RPC clients: %w", err)
+	if as.db == nil {
+		if err := as.initDB(ctx, cfg.MasterDb); err != nil {
+			return fmt.Errorf("failed to init DB: %w", err)
+		}
+	}
+
+	cfg, err := as.applyRegistry(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load chain and token registry: %w", err)
 	}
+	as.cfg = cfg
+	as.symbolMap = symbols.NewMap(cfg.Symbols)
 
-	if err := as.initDB(ctx, cfg.MasterDb); err != nil {
-		return fmt.Errorf("failed to init DB: %w", err)
+	if err := as.initRPCClients(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to start RPC clients: %w", err)
 	}
 
 	if err := as.initSynchronizer(cfg); err != nil {
@@ -114,28 +188,76 @@ func (as *GasOracle) initFromConfig(ctx context.Context, cfg *config.Config) err
 		return fmt.Errorf("failed to init work handle: %w", err)
 	}
 
+	if cfg.LeaderElection.Enable {
+		if err := as.initElector(); err != nil {
+			return fmt.Errorf("failed to init leader election: %w", err)
+		}
+	}
+
 	return nil
 }
 
 func (as *GasOracle) initRPCClients(ctx context.Context, conf *config.Config) error {
 	for i := range conf.RPCs {
-		log.Info("Init rpc client", "ChainId", conf.RPCs[i].ChainId, "RpcUrl", conf.RPCs[i].RpcUrl)
-		rpc := conf.RPCs[i]
-		ethClient, err := node.DialEthClient(ctx, rpc.RpcUrl)
-		if err != nil {
-			log.Error("dial eth client fail", "err", err)
-			return fmt.Errorf("failed to dial L1 client: %w", err)
-		}
-		if as.ethClient == nil {
-			as.ethClient = make(map[uint64]node.EthClient)
+		if err := as.initRPCClient(ctx, *conf.RPCs[i]); err != nil {
+			return err
 		}
-		as.ethClient[rpc.ChainId] = ethClient
-		as.chainIdList = append(as.chainIdList, rpc.ChainId)
 	}
 	log.Info("Init rpc client success")
 	return nil
 }
 
+func (as *GasOracle) initRPCClient(ctx context.Context, rpc config.RPC) error {
+	ethClient, err := as.dialRPCClient(ctx, rpc)
+	if err != nil {
+		return err
+	}
+	as.addRPCClient(rpc, ethClient)
+	return nil
+}
+
+func (as *GasOracle) dialRPCClient(ctx context.Context, rpc config.RPC) (node.EthClient, error) {
+	var ethClient node.EthClient
+	var err error
+	for _, rpcUrl := range rpc.RpcUrls() {
+		log.Info("Init rpc client", "ChainId", rpc.ChainId, "RpcUrl", node.RedactURL(rpcUrl))
+		ethClient, err = node.DialEthClient(ctx, rpcUrl)
+		if err == nil {
+			break
+		}
+		log.Error("dial eth client fail", "err", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial L1 client: %w", err)
+	}
+	return ethClient, nil
+}
+
+func (as *GasOracle) addRPCClient(rpc config.RPC, ethClient node.EthClient) {
+	as.clientMu.Lock()
+	if as.ethClient == nil {
+		as.ethClient = make(map[uint64]node.EthClient)
+		as.rpcs = make(map[uint64]config.RPC)
+	}
+	as.ethClient[rpc.ChainId] = ethClient
+	as.clientMu.Unlock()
+	as.rpcs[rpc.ChainId] = rpc
+	as.chainIdList = append(as.chainIdList, rpc.ChainId)
+}
+
+// applyRegistry returns a copy of cfg with the chains and tokens managed in
+// the database merged in. Registry rows that would make it invalid are skipped.
+func (as *GasOracle) applyRegistry(cfg *config.Config) (*config.Config, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	merged := *cfg
+	if err := as.db.ApplyRegistry(&merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
 func (as *GasOracle) initDB(ctx context.Context, cfg config.Database) error {
 	db, err := database.NewDB(ctx, cfg)
 	if err != nil {
@@ -148,41 +270,115 @@ func (as *GasOracle) initDB(ctx context.Context, cfg config.Database) error {
 
 func (as *GasOracle) initSynchronizer(config *config.Config) error {
 	for i := range config.RPCs {
-		log.Info("Init synchronizer success", "chainId", config.RPCs[i].ChainId)
-		rpcItem := config.RPCs[i]
-
-		synchronizerTemp, err := synchronizer.NewOracleSynchronizer(as.db, as.ethClient[config.RPCs[i].ChainId], as.backOffset, rpcItem.ChainId, rpcItem.NativeToken, rpcItem.Decimal, as.loopInternal, as.shutdown)
-		if err != nil {
-			log.Error("new oracle synchronizer fail", "err", err)
+		if err := as.initChainSynchronizer(*config.RPCs[i]); err != nil {
 			return err
 		}
-		if as.synchronizer == nil {
-			as.synchronizer = make(map[uint64]*synchronizer.OracleSynchronizer)
-		}
-		as.synchronizer[rpcItem.ChainId] = synchronizerTemp
 	}
 	return nil
 }
 
+func (as *GasOracle) initChainSynchronizer(rpcItem config.RPC) error {
+	log.Info("Init synchronizer success", "chainId", rpcItem.ChainId)
+	backOffset, loopInternal := as.backOffset, as.loopInternal
+	if rpcItem.BackOffset != 0 {
+		backOffset = rpcItem.BackOffset
+	}
+	if rpcItem.LoopInternal != 0 {
+		loopInternal = rpcItem.LoopInternal
+	}
+	nativeSymbol := as.symbolMap.Canonical(rpcItem.NativeToken)
+	synchronizerTemp, err := synchronizer.NewOracleSynchronizer(as.db, as.bus, as.ethClient[rpcItem.ChainId], backOffset, rpcItem.ChainId, nativeSymbol, rpcItem.Decimal, loopInternal, as.shutdown)
+	if err != nil {
+		log.Error("new oracle synchronizer fail", "err", err)
+		return err
+	}
+	if as.synchronizer == nil {
+		as.synchronizer = make(map[uint64]*synchronizer.OracleSynchronizer)
+	}
+	as.synchronizer[rpcItem.ChainId] = synchronizerTemp
+	return nil
+}
+
 func (as *GasOracle) initWorkerHandle(config *config.Config) error {
+	wConf := &worker.WorkerHandleConfig{
+		BaseUrl:          config.SkyeyeUrl,
+		LoopInterval:     config.PriceLoopInterval,
+		FetchConcurrency: config.PriceFetchConcurrency,
+		RequestTimeout:   config.PriceRequestTimeout,
+		SymbolList:       workerSymbols(config.Symbols),
+		SymbolMap:        symbols.NewMap(config.Symbols),
+		QuoteCurrencies:  config.QuoteCurrencyList(),
+		DexPools:         workerDexPools(config.DexPools),
+		ChainlinkFeeds:   workerChainlinkFeeds(config.ChainlinkFeeds),
+		PriceGuard:       workerPriceGuard(config.PriceGuard),
+		ChainClient:      as.chainClient,
+	}
+	handle, err := worker.NewWorkerHandle(as.db, as.bus, wConf, as.shutdown)
+	if err != nil {
+		log.Error("new work handle fail", "err", err)
+		return err
+	}
+	as.workerHandle = handle
+	return nil
+}
+
+func workerSymbols(symbols []config.Symbols) []worker.Symbols {
 	var symbolList []worker.Symbols
-	for _, symbol := range config.Symbols {
+	for _, symbol := range symbols {
 		item := worker.Symbols{
 			Name:    symbol.Name,
 			Decimal: symbol.Decimal,
 		}
 		symbolList = append(symbolList, item)
 	}
-	wConf := &worker.WorkerHandleConfig{
-		BaseUrl:      config.SkyeyeUrl,
-		LoopInterval: time.Second * 5,
-		SymbolList:   symbolList,
+	return symbolList
+}
+
+func workerDexPools(pools []config.DexPool) []worker.DexPool {
+	var dexPools []worker.DexPool
+	for _, pool := range pools {
+		dexPools = append(dexPools, worker.DexPool{
+			ChainId:    pool.ChainId,
+			Address:    common.HexToAddress(pool.Address),
+			Version:    pool.Version,
+			Token0:     pool.Token0,
+			Token1:     pool.Token1,
+			TwapWindow: pool.TwapWindow,
+		})
 	}
-	handle, err := worker.NewWorkerHandle(as.db, wConf, as.shutdown)
-	if err != nil {
-		log.Error("new work handle fail", "err", err)
-		return err
+	return dexPools
+}
+
+func workerChainlinkFeeds(feeds []config.ChainlinkFeed) []worker.ChainlinkFeed {
+	var chainlinkFeeds []worker.ChainlinkFeed
+	for _, feed := range feeds {
+		chainlinkFeeds = append(chainlinkFeeds, worker.ChainlinkFeed{
+			ChainId: feed.ChainId,
+			Address: common.HexToAddress(feed.Address),
+			Base:    feed.Base,
+			Quote:   feed.Quote,
+			MaxAge:  feed.MaxAge,
+			Primary: feed.Primary,
+		})
 	}
-	as.workerHandle = handle
-	return nil
+	return chainlinkFeeds
+}
+
+func workerPriceGuard(guard config.PriceGuard) worker.PriceGuard {
+	confirmations := guard.Confirmations
+	if confirmations == 0 {
+		confirmations = config.DefaultPriceConfirmations
+	}
+	return worker.PriceGuard{
+		MaxDeviation:  guard.MaxDeviation,
+		Window:        guard.Window,
+		Confirmations: confirmations,
+	}
+}
+
+func (as *GasOracle) chainClient(chainId uint64) (node.EthClient, bool) {
+	as.clientMu.RLock()
+	defer as.clientMu.RUnlock()
+	client, ok := as.ethClient[chainId]
+	return client, ok
 }
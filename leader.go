@@ -0,0 +1,108 @@
+package gas_oracle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/election"
+)
+
+const (
+	chainLeasePrefix = "chain:"
+	workerLeaseKey   = "worker"
+)
+
+func chainLeaseKey(chainId uint64) string {
+	return chainLeasePrefix + strconv.FormatUint(chainId, 10)
+}
+
+func (as *GasOracle) initElector() error {
+	elector, err := election.NewElector(as.db, as.cfg.LeaderElection, as, as.shutdown)
+	if err != nil {
+		return err
+	}
+	as.elector = elector
+	return nil
+}
+
+// LeaseKeys is one lease per configured chain plus one for the price worker.
+func (as *GasOracle) LeaseKeys() []string {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	keys := make([]string, 0, len(as.chainIdList)+1)
+	for _, chainId := range as.chainIdList {
+		keys = append(keys, chainLeaseKey(chainId))
+	}
+	return append(keys, workerLeaseKey)
+}
+
+// Acquired starts sampling the chain, or pricing the symbols, this replica
+// now holds the lease of.
+func (as *GasOracle) Acquired(key string) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if as.stopped.Load() {
+		return fmt.Errorf("gas oracle stopped")
+	}
+
+	if key == workerLeaseKey {
+		if as.workerHandle == nil {
+			if err := as.initWorkerHandle(as.cfg); err != nil {
+				return err
+			}
+		}
+		return as.workerHandle.Start()
+	}
+
+	chainId, err := strconv.ParseUint(strings.TrimPrefix(key, chainLeasePrefix), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid lease key %s: %w", key, err)
+	}
+	rpc, ok := as.rpcs[chainId]
+	if !ok {
+		return fmt.Errorf("chain %d is not configured", chainId)
+	}
+	// a synchronizer can only be started once, so replace the one left from
+	// construction or from a previous term
+	if syncer, ok := as.synchronizer[chainId]; ok {
+		if err := syncer.Stop(context.Background()); err != nil {
+			log.Warn("stop previous synchronizer fail", "chainId", chainId, "err", err)
+		}
+	}
+	if err := as.initChainSynchronizer(rpc); err != nil {
+		return err
+	}
+	log.Info("starting sync", "chainId", chainId)
+	return as.synchronizer[chainId].Start(context.Background())
+}
+
+// Lost stops the work guarded by key, keeping the rpc client for a later term.
+func (as *GasOracle) Lost(key string) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if key == workerLeaseKey {
+		if as.workerHandle != nil {
+			if err := as.workerHandle.Close(); err != nil {
+				log.Error("close work handle fail", "err", err)
+			}
+			as.workerHandle = nil
+		}
+		return
+	}
+
+	chainId, err := strconv.ParseUint(strings.TrimPrefix(key, chainLeasePrefix), 10, 64)
+	if err != nil {
+		return
+	}
+	if syncer, ok := as.synchronizer[chainId]; ok {
+		if err := syncer.Stop(context.Background()); err != nil {
+			log.Error("stop synchronizer fail", "chainId", chainId, "err", err)
+		}
+		delete(as.synchronizer, chainId)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS token_price;
+DROP TABLE IF EXISTS gas_fee;
+DROP DOMAIN IF EXISTS UINT256;
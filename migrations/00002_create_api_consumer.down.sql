@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_consumer_audit;
+DROP TABLE IF EXISTS api_consumer;
@@ -0,0 +1,19 @@
+create table if not exists api_consumer(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    name                   VARCHAR NOT NULL,
+    token                  VARCHAR NOT NULL,
+    enabled                BOOLEAN DEFAULT true,
+    expire_at              INTEGER DEFAULT 0, -- unix seconds, 0 means never expire --
+    timestamp              INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS api_consumer_token ON api_consumer(token);
+
+
+create table if not exists api_consumer_audit(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    consumer_name          VARCHAR,
+    method                 VARCHAR,
+    peer_addr              VARCHAR,
+    timestamp              INTEGER
+);
+CREATE INDEX IF NOT EXISTS api_consumer_audit_consumer_name ON api_consumer_audit(consumer_name);
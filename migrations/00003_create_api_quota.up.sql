@@ -0,0 +1,8 @@
+create table if not exists api_quota(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    quota_key              VARCHAR NOT NULL, -- consumer name, or ip:<addr> for anonymous callers --
+    day                    VARCHAR NOT NULL,
+    request_count          INTEGER DEFAULT 0,
+    timestamp              INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS api_quota_key_day ON api_quota(quota_key, day);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS gas_fee_history;
+
+ALTER TABLE gas_fee DROP COLUMN IF EXISTS priority_fee;
+ALTER TABLE gas_fee DROP COLUMN IF EXISTS gas_price;
+ALTER TABLE gas_fee DROP COLUMN IF EXISTS base_fee;
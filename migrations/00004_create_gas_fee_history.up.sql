@@ -0,0 +1,19 @@
+ALTER TABLE gas_fee ADD COLUMN IF NOT EXISTS base_fee UINT256;
+ALTER TABLE gas_fee ADD COLUMN IF NOT EXISTS gas_price UINT256;
+ALTER TABLE gas_fee ADD COLUMN IF NOT EXISTS priority_fee UINT256;
+
+
+create table if not exists gas_fee_history(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    chain_id               UINT256,
+    block_number           UINT256,
+    base_fee               UINT256,
+    gas_used_ratio         VARCHAR,
+    priority_fee_min       UINT256, -- priority fee percentiles 0/25/50/75/100 of the block transactions --
+    priority_fee_p25       UINT256,
+    priority_fee_p50       UINT256,
+    priority_fee_p75       UINT256,
+    priority_fee_max       UINT256,
+    timestamp              INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS gas_fee_history_chain_id_block_number ON gas_fee_history(chain_id, block_number);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS token_config;
+DROP TABLE IF EXISTS chain_config;
@@ -0,0 +1,24 @@
+create table if not exists chain_config(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    chain_id               UINT256 NOT NULL,
+    chain_name             VARCHAR,
+    rpc_urls               TEXT, -- json array, dialed in order --
+    native_token           VARCHAR,
+    decimal                SMALLINT DEFAULT 18,
+    enabled                BOOLEAN DEFAULT true,
+    back_offset            INTEGER DEFAULT 0, -- 0 falls back to the global back_offset --
+    loop_interval          INTEGER DEFAULT 0, -- seconds, 0 falls back to the global loop_internal --
+    timestamp              INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS chain_config_chain_id ON chain_config(chain_id);
+
+
+create table if not exists token_config(
+    guid                   TEXT PRIMARY KEY DEFAULT replace(uuid_generate_v4()::text, '-', ''),
+    symbol                 VARCHAR NOT NULL,
+    skeye_symbol           VARCHAR, -- symbol queried from skyeye, empty means the same as symbol --
+    decimal                SMALLINT DEFAULT 18,
+    enabled                BOOLEAN DEFAULT true,
+    timestamp              INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS token_config_symbol ON token_config(symbol);
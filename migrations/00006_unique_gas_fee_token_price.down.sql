@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS token_price_token_symbol_unique;
+CREATE INDEX IF NOT EXISTS token_price_token_symbol ON token_price(token_symbol);
+
+DROP INDEX IF EXISTS gas_fee_chain_id_unique;
+CREATE INDEX IF NOT EXISTS gas_fee_chain_id ON gas_fee(chain_id);
@@ -0,0 +1,15 @@
+-- keep only the latest row per chain and symbol before enforcing uniqueness --
+DELETE FROM gas_fee a USING gas_fee b
+WHERE a.chain_id = b.chain_id
+  AND (COALESCE(a.timestamp, 0) < COALESCE(b.timestamp, 0)
+    OR (COALESCE(a.timestamp, 0) = COALESCE(b.timestamp, 0) AND a.guid < b.guid));
+DROP INDEX IF EXISTS gas_fee_chain_id;
+CREATE UNIQUE INDEX IF NOT EXISTS gas_fee_chain_id_unique ON gas_fee(chain_id);
+
+
+DELETE FROM token_price a USING token_price b
+WHERE a.token_symbol = b.token_symbol
+  AND (COALESCE(a.timestamp, 0) < COALESCE(b.timestamp, 0)
+    OR (COALESCE(a.timestamp, 0) = COALESCE(b.timestamp, 0) AND a.guid < b.guid));
+DROP INDEX IF EXISTS token_price_token_symbol;
+CREATE UNIQUE INDEX IF NOT EXISTS token_price_token_symbol_unique ON token_price(token_symbol);
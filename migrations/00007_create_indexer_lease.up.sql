@@ -0,0 +1,6 @@
+create table if not exists indexer_lease(
+    lease_key              VARCHAR PRIMARY KEY, -- chain:<id>, worker or replica:<holder> --
+    holder                 VARCHAR NOT NULL,
+    expire_at              BIGINT NOT NULL, -- unix milliseconds of the database clock --
+    timestamp              INTEGER
+);
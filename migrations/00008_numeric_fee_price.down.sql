@@ -0,0 +1,3 @@
+ALTER TABLE gas_fee ALTER COLUMN predict_fee TYPE VARCHAR USING predict_fee::TEXT;
+-- drop the trailing zeros of the fixed 18 digit scale, without trim_scale which needs postgres 13 --
+ALTER TABLE token_price ALTER COLUMN market_price TYPE VARCHAR USING rtrim(rtrim(market_price::TEXT, '0'), '.');
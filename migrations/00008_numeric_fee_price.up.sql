@@ -0,0 +1,22 @@
+-- values that never held a number can not be converted, they are left NULL until the next sampling round writes them again --
+-- prices were written with %f and cheap tokens truncated to 0.000000, they are left NULL so the worker refills them at full precision --
+DO
+$$
+    DECLARE
+        bad_fees   BIGINT;
+        bad_prices BIGINT;
+        truncated  BIGINT;
+    BEGIN
+        UPDATE gas_fee SET predict_fee = NULL WHERE predict_fee !~ '^\s*[0-9]+\s*$';
+        GET DIAGNOSTICS bad_fees = ROW_COUNT;
+        UPDATE token_price SET market_price = NULL WHERE market_price !~ '^\s*[0-9]+(\.[0-9]+)?\s*$';
+        GET DIAGNOSTICS bad_prices = ROW_COUNT;
+        UPDATE token_price SET market_price = NULL WHERE trim(market_price)::NUMERIC = 0;
+        GET DIAGNOSTICS truncated = ROW_COUNT;
+        RAISE NOTICE 'numeric_fee_price: set % predict fees and % market prices that are no numbers and % market prices truncated to 0 to NULL',
+            bad_fees, bad_prices, truncated;
+    END
+$$;
+
+ALTER TABLE gas_fee ALTER COLUMN predict_fee TYPE UINT256 USING trim(predict_fee)::UINT256;
+ALTER TABLE token_price ALTER COLUMN market_price TYPE NUMERIC(78, 18) USING trim(market_price)::NUMERIC(78, 18);
@@ -0,0 +1,4 @@
+DELETE FROM token_price WHERE quote_currency <> 'USD';
+DROP INDEX IF EXISTS token_price_symbol_quote_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS token_price_token_symbol_unique ON token_price(token_symbol);
+ALTER TABLE token_price DROP COLUMN IF EXISTS quote_currency;
@@ -0,0 +1,4 @@
+-- prices stored so far came from skyeye in USD --
+ALTER TABLE token_price ADD COLUMN IF NOT EXISTS quote_currency VARCHAR NOT NULL DEFAULT 'USD';
+DROP INDEX IF EXISTS token_price_token_symbol_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS token_price_symbol_quote_unique ON token_price(token_symbol, quote_currency);
@@ -0,0 +1,2 @@
+ALTER TABLE token_price DROP COLUMN IF EXISTS confidence;
+ALTER TABLE token_price DROP COLUMN IF EXISTS derivation_path;
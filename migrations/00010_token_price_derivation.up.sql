@@ -0,0 +1,3 @@
+-- prices stored so far came straight from skyeye --
+ALTER TABLE token_price ADD COLUMN IF NOT EXISTS derivation_path VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE token_price ADD COLUMN IF NOT EXISTS confidence DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE token_price DROP COLUMN IF EXISTS price_state_reason;
+ALTER TABLE token_price DROP COLUMN IF EXISTS price_state;
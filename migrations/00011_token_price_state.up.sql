@@ -0,0 +1,3 @@
+-- a frozen price is the last good one kept while new samples are rejected --
+ALTER TABLE token_price ADD COLUMN IF NOT EXISTS price_state VARCHAR NOT NULL DEFAULT 'ok';
+ALTER TABLE token_price ADD COLUMN IF NOT EXISTS price_state_reason VARCHAR NOT NULL DEFAULT '';
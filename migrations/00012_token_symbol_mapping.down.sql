@@ -0,0 +1,4 @@
+ALTER TABLE token_price ALTER COLUMN skeye_symbol DROP NOT NULL;
+ALTER TABLE token_price ALTER COLUMN skeye_symbol DROP DEFAULT;
+ALTER TABLE token_config DROP COLUMN IF EXISTS addresses;
+ALTER TABLE token_config DROP COLUMN IF EXISTS aliases;
@@ -0,0 +1,7 @@
+-- aliases is a json array of other names of the symbol, addresses a json object of token contracts by chain id --
+ALTER TABLE token_config ADD COLUMN IF NOT EXISTS aliases TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE token_config ADD COLUMN IF NOT EXISTS addresses TEXT NOT NULL DEFAULT '{}';
+-- the symbol the price was queried from skyeye with --
+UPDATE token_price SET skeye_symbol = token_symbol WHERE skeye_symbol IS NULL;
+ALTER TABLE token_price ALTER COLUMN skeye_symbol SET DEFAULT '';
+ALTER TABLE token_price ALTER COLUMN skeye_symbol SET NOT NULL;
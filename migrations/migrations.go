@@ -0,0 +1,11 @@
+// Package migrations embeds the versioned sql migrations into the binary.
+//
+// Every version has a <version>_<name>.up.sql file and, to be revertible, a
+// matching <version>_<name>.down.sql file. Applied migrations must not be
+// edited; add a new version instead.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
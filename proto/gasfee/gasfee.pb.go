@@ -26,6 +26,7 @@ type TokenGasPriceRequest struct {
 	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
 	ChainId       uint64                 `protobuf:"varint,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	QuoteCurrency string                 `protobuf:"bytes,4,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,15 +82,25 @@ func (x *TokenGasPriceRequest) GetSymbol() string {
 	return ""
 }
 
+func (x *TokenGasPriceRequest) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
 type TokenGasPriceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReturnCode    uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	MarketPrice   string                 `protobuf:"bytes,3,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
-	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	PredictFee    string                 `protobuf:"bytes,5,opt,name=predict_fee,json=predictFee,proto3" json:"predict_fee,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode       uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	MarketPrice      string                 `protobuf:"bytes,3,opt,name=market_price,json=marketPrice,proto3" json:"market_price,omitempty"`
+	Symbol           string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	PredictFee       string                 `protobuf:"bytes,5,opt,name=predict_fee,json=predictFee,proto3" json:"predict_fee,omitempty"`
+	QuoteCurrency    string                 `protobuf:"bytes,6,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
+	PriceState       string                 `protobuf:"bytes,7,opt,name=price_state,json=priceState,proto3" json:"price_state,omitempty"`
+	PriceStateReason string                 `protobuf:"bytes,8,opt,name=price_state_reason,json=priceStateReason,proto3" json:"price_state_reason,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *TokenGasPriceResponse) Reset() {
@@ -157,25 +168,1139 @@ func (x *TokenGasPriceResponse) GetPredictFee() string {
 	return ""
 }
 
-var File_proto_gasfee_proto protoreflect.FileDescriptor
+func (x *TokenGasPriceResponse) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
 
-const file_proto_gasfee_proto_rawDesc = "" +
-	"\n" +
-	"\x12proto/gasfee.proto\x12\x0ecpchain.gasfee\"p\n" +
-	"\x14TokenGasPriceRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x19\n" +
-	"\bchain_id\x18\x02 \x01(\x04R\achainId\x12\x16\n" +
-	"\x06symbol\x18\x03 \x01(\tR\x06symbol\"\xae\x01\n" +
-	"\x15TokenGasPriceResponse\x12\x1f\n" +
-	"\vreturn_code\x18\x01 \x01(\x04R\n" +
-	"returnCode\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
-	"\fmarket_price\x18\x03 \x01(\tR\vmarketPrice\x12\x16\n" +
-	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x1f\n" +
-	"\vpredict_fee\x18\x05 \x01(\tR\n" +
-	"predictFee2\x86\x01\n" +
+func (x *TokenGasPriceResponse) GetPriceState() string {
+	if x != nil {
+		return x.PriceState
+	}
+	return ""
+}
+
+func (x *TokenGasPriceResponse) GetPriceStateReason() string {
+	if x != nil {
+		return x.PriceStateReason
+	}
+	return ""
+}
+
+type ListSupportedChainsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSupportedChainsRequest) Reset() {
+	*x = ListSupportedChainsRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSupportedChainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedChainsRequest) ProtoMessage() {}
+
+func (x *ListSupportedChainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedChainsRequest.ProtoReflect.Descriptor instead.
+func (*ListSupportedChainsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListSupportedChainsRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+type SupportedChain struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       uint64                 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	NativeToken   string                 `protobuf:"bytes,3,opt,name=native_token,json=nativeToken,proto3" json:"native_token,omitempty"`
+	Decimal       uint32                 `protobuf:"varint,4,opt,name=decimal,proto3" json:"decimal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupportedChain) Reset() {
+	*x = SupportedChain{}
+	mi := &file_proto_gasfee_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupportedChain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupportedChain) ProtoMessage() {}
+
+func (x *SupportedChain) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupportedChain.ProtoReflect.Descriptor instead.
+func (*SupportedChain) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SupportedChain) GetChainId() uint64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *SupportedChain) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *SupportedChain) GetNativeToken() string {
+	if x != nil {
+		return x.NativeToken
+	}
+	return ""
+}
+
+func (x *SupportedChain) GetDecimal() uint32 {
+	if x != nil {
+		return x.Decimal
+	}
+	return 0
+}
+
+type ListSupportedChainsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode    uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Chains        []*SupportedChain      `protobuf:"bytes,3,rep,name=chains,proto3" json:"chains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSupportedChainsResponse) Reset() {
+	*x = ListSupportedChainsResponse{}
+	mi := &file_proto_gasfee_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSupportedChainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedChainsResponse) ProtoMessage() {}
+
+func (x *ListSupportedChainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedChainsResponse.ProtoReflect.Descriptor instead.
+func (*ListSupportedChainsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSupportedChainsResponse) GetReturnCode() uint64 {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return 0
+}
+
+func (x *ListSupportedChainsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListSupportedChainsResponse) GetChains() []*SupportedChain {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
+type ListSupportedTokensRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSupportedTokensRequest) Reset() {
+	*x = ListSupportedTokensRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSupportedTokensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedTokensRequest) ProtoMessage() {}
+
+func (x *ListSupportedTokensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedTokensRequest.ProtoReflect.Descriptor instead.
+func (*ListSupportedTokensRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSupportedTokensRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+type TokenAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       uint64                 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenAddress) Reset() {
+	*x = TokenAddress{}
+	mi := &file_proto_gasfee_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenAddress) ProtoMessage() {}
+
+func (x *TokenAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenAddress.ProtoReflect.Descriptor instead.
+func (*TokenAddress) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TokenAddress) GetChainId() uint64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *TokenAddress) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type SupportedToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimal       uint32                 `protobuf:"varint,2,opt,name=decimal,proto3" json:"decimal,omitempty"`
+	Aliases       []string               `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Addresses     []*TokenAddress        `protobuf:"bytes,4,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupportedToken) Reset() {
+	*x = SupportedToken{}
+	mi := &file_proto_gasfee_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupportedToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupportedToken) ProtoMessage() {}
+
+func (x *SupportedToken) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupportedToken.ProtoReflect.Descriptor instead.
+func (*SupportedToken) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SupportedToken) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SupportedToken) GetDecimal() uint32 {
+	if x != nil {
+		return x.Decimal
+	}
+	return 0
+}
+
+func (x *SupportedToken) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *SupportedToken) GetAddresses() []*TokenAddress {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type ListSupportedTokensResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode      uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Tokens          []*SupportedToken      `protobuf:"bytes,3,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	QuoteCurrencies []string               `protobuf:"bytes,4,rep,name=quote_currencies,json=quoteCurrencies,proto3" json:"quote_currencies,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListSupportedTokensResponse) Reset() {
+	*x = ListSupportedTokensResponse{}
+	mi := &file_proto_gasfee_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSupportedTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSupportedTokensResponse) ProtoMessage() {}
+
+func (x *ListSupportedTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSupportedTokensResponse.ProtoReflect.Descriptor instead.
+func (*ListSupportedTokensResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSupportedTokensResponse) GetReturnCode() uint64 {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return 0
+}
+
+func (x *ListSupportedTokensResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListSupportedTokensResponse) GetTokens() []*SupportedToken {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *ListSupportedTokensResponse) GetQuoteCurrencies() []string {
+	if x != nil {
+		return x.QuoteCurrencies
+	}
+	return nil
+}
+
+type ChainConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       uint64                 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	RpcUrls       []string               `protobuf:"bytes,3,rep,name=rpc_urls,json=rpcUrls,proto3" json:"rpc_urls,omitempty"`
+	NativeToken   string                 `protobuf:"bytes,4,opt,name=native_token,json=nativeToken,proto3" json:"native_token,omitempty"`
+	Decimal       uint32                 `protobuf:"varint,5,opt,name=decimal,proto3" json:"decimal,omitempty"`
+	Enabled       bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	BackOffset    uint64                 `protobuf:"varint,7,opt,name=back_offset,json=backOffset,proto3" json:"back_offset,omitempty"`
+	LoopInterval  uint64                 `protobuf:"varint,8,opt,name=loop_interval,json=loopInterval,proto3" json:"loop_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChainConfig) Reset() {
+	*x = ChainConfig{}
+	mi := &file_proto_gasfee_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChainConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainConfig) ProtoMessage() {}
+
+func (x *ChainConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainConfig.ProtoReflect.Descriptor instead.
+func (*ChainConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ChainConfig) GetChainId() uint64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+func (x *ChainConfig) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ChainConfig) GetRpcUrls() []string {
+	if x != nil {
+		return x.RpcUrls
+	}
+	return nil
+}
+
+func (x *ChainConfig) GetNativeToken() string {
+	if x != nil {
+		return x.NativeToken
+	}
+	return ""
+}
+
+func (x *ChainConfig) GetDecimal() uint32 {
+	if x != nil {
+		return x.Decimal
+	}
+	return 0
+}
+
+func (x *ChainConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ChainConfig) GetBackOffset() uint64 {
+	if x != nil {
+		return x.BackOffset
+	}
+	return 0
+}
+
+func (x *ChainConfig) GetLoopInterval() uint64 {
+	if x != nil {
+		return x.LoopInterval
+	}
+	return 0
+}
+
+type TokenConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	SkeyeSymbol   string                 `protobuf:"bytes,2,opt,name=skeye_symbol,json=skeyeSymbol,proto3" json:"skeye_symbol,omitempty"`
+	Decimal       uint32                 `protobuf:"varint,3,opt,name=decimal,proto3" json:"decimal,omitempty"`
+	Enabled       bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Aliases       []string               `protobuf:"bytes,5,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Addresses     []*TokenAddress        `protobuf:"bytes,6,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenConfig) Reset() {
+	*x = TokenConfig{}
+	mi := &file_proto_gasfee_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenConfig) ProtoMessage() {}
+
+func (x *TokenConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenConfig.ProtoReflect.Descriptor instead.
+func (*TokenConfig) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TokenConfig) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TokenConfig) GetSkeyeSymbol() string {
+	if x != nil {
+		return x.SkeyeSymbol
+	}
+	return ""
+}
+
+func (x *TokenConfig) GetDecimal() uint32 {
+	if x != nil {
+		return x.Decimal
+	}
+	return 0
+}
+
+func (x *TokenConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *TokenConfig) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *TokenConfig) GetAddresses() []*TokenAddress {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type UpsertChainConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chain         *ChainConfig           `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertChainConfigRequest) Reset() {
+	*x = UpsertChainConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertChainConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertChainConfigRequest) ProtoMessage() {}
+
+func (x *UpsertChainConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertChainConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpsertChainConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpsertChainConfigRequest) GetChain() *ChainConfig {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+type DeleteChainConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainId       uint64                 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteChainConfigRequest) Reset() {
+	*x = DeleteChainConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteChainConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteChainConfigRequest) ProtoMessage() {}
+
+func (x *DeleteChainConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteChainConfigRequest.ProtoReflect.Descriptor instead.
+func (*DeleteChainConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteChainConfigRequest) GetChainId() uint64 {
+	if x != nil {
+		return x.ChainId
+	}
+	return 0
+}
+
+type ListChainConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChainConfigRequest) Reset() {
+	*x = ListChainConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChainConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChainConfigRequest) ProtoMessage() {}
+
+func (x *ListChainConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChainConfigRequest.ProtoReflect.Descriptor instead.
+func (*ListChainConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{13}
+}
+
+type ListChainConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode    uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Chains        []*ChainConfig         `protobuf:"bytes,3,rep,name=chains,proto3" json:"chains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChainConfigResponse) Reset() {
+	*x = ListChainConfigResponse{}
+	mi := &file_proto_gasfee_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChainConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChainConfigResponse) ProtoMessage() {}
+
+func (x *ListChainConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChainConfigResponse.ProtoReflect.Descriptor instead.
+func (*ListChainConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListChainConfigResponse) GetReturnCode() uint64 {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return 0
+}
+
+func (x *ListChainConfigResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListChainConfigResponse) GetChains() []*ChainConfig {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
+type UpsertTokenConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *TokenConfig           `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertTokenConfigRequest) Reset() {
+	*x = UpsertTokenConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertTokenConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertTokenConfigRequest) ProtoMessage() {}
+
+func (x *UpsertTokenConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTokenConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpsertTokenConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpsertTokenConfigRequest) GetToken() *TokenConfig {
+	if x != nil {
+		return x.Token
+	}
+	return nil
+}
+
+type DeleteTokenConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTokenConfigRequest) Reset() {
+	*x = DeleteTokenConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTokenConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTokenConfigRequest) ProtoMessage() {}
+
+func (x *DeleteTokenConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTokenConfigRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTokenConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteTokenConfigRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ListTokenConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokenConfigRequest) Reset() {
+	*x = ListTokenConfigRequest{}
+	mi := &file_proto_gasfee_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokenConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokenConfigRequest) ProtoMessage() {}
+
+func (x *ListTokenConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokenConfigRequest.ProtoReflect.Descriptor instead.
+func (*ListTokenConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{17}
+}
+
+type ListTokenConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode    uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Tokens        []*TokenConfig         `protobuf:"bytes,3,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTokenConfigResponse) Reset() {
+	*x = ListTokenConfigResponse{}
+	mi := &file_proto_gasfee_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTokenConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTokenConfigResponse) ProtoMessage() {}
+
+func (x *ListTokenConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTokenConfigResponse.ProtoReflect.Descriptor instead.
+func (*ListTokenConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListTokenConfigResponse) GetReturnCode() uint64 {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return 0
+}
+
+func (x *ListTokenConfigResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListTokenConfigResponse) GetTokens() []*TokenConfig {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+type AdminResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnCode    uint64                 `protobuf:"varint,1,opt,name=return_code,json=returnCode,proto3" json:"return_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminResponse) Reset() {
+	*x = AdminResponse{}
+	mi := &file_proto_gasfee_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminResponse) ProtoMessage() {}
+
+func (x *AdminResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gasfee_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminResponse.ProtoReflect.Descriptor instead.
+func (*AdminResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gasfee_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AdminResponse) GetReturnCode() uint64 {
+	if x != nil {
+		return x.ReturnCode
+	}
+	return 0
+}
+
+func (x *AdminResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_gasfee_proto protoreflect.FileDescriptor
+
+const file_proto_gasfee_proto_rawDesc = "" +
+	"\n" +
+	"\x12proto/gasfee.proto\x12\x0ecpchain.gasfee\"\x97\x01\n" +
+	"\x14TokenGasPriceRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x19\n" +
+	"\bchain_id\x18\x02 \x01(\x04R\achainId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12%\n" +
+	"\x0equote_currency\x18\x04 \x01(\tR\rquoteCurrency\"\xa4\x02\n" +
+	"\x15TokenGasPriceResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
+	"\fmarket_price\x18\x03 \x01(\tR\vmarketPrice\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x1f\n" +
+	"\vpredict_fee\x18\x05 \x01(\tR\n" +
+	"predictFee\x12%\n" +
+	"\x0equote_currency\x18\x06 \x01(\tR\rquoteCurrency\x12\x1f\n" +
+	"\vprice_state\x18\a \x01(\tR\n" +
+	"priceState\x12,\n" +
+	"\x12price_state_reason\x18\b \x01(\tR\x10priceStateReason\"C\n" +
+	"\x1aListSupportedChainsRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\"\x87\x01\n" +
+	"\x0eSupportedChain\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x04R\achainId\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12!\n" +
+	"\fnative_token\x18\x03 \x01(\tR\vnativeToken\x12\x18\n" +
+	"\adecimal\x18\x04 \x01(\rR\adecimal\"\x90\x01\n" +
+	"\x1bListSupportedChainsResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x126\n" +
+	"\x06chains\x18\x03 \x03(\v2\x1e.cpchain.gasfee.SupportedChainR\x06chains\"C\n" +
+	"\x1aListSupportedTokensRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\"C\n" +
+	"\fTokenAddress\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x04R\achainId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\x98\x01\n" +
+	"\x0eSupportedToken\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x18\n" +
+	"\adecimal\x18\x02 \x01(\rR\adecimal\x12\x18\n" +
+	"\aaliases\x18\x03 \x03(\tR\aaliases\x12:\n" +
+	"\taddresses\x18\x04 \x03(\v2\x1c.cpchain.gasfee.TokenAddressR\taddresses\"\xbb\x01\n" +
+	"\x1bListSupportedTokensResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x126\n" +
+	"\x06tokens\x18\x03 \x03(\v2\x1e.cpchain.gasfee.SupportedTokenR\x06tokens\x12)\n" +
+	"\x10quote_currencies\x18\x04 \x03(\tR\x0fquoteCurrencies\"\xff\x01\n" +
+	"\vChainConfig\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x04R\achainId\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x19\n" +
+	"\brpc_urls\x18\x03 \x03(\tR\arpcUrls\x12!\n" +
+	"\fnative_token\x18\x04 \x01(\tR\vnativeToken\x12\x18\n" +
+	"\adecimal\x18\x05 \x01(\rR\adecimal\x12\x18\n" +
+	"\aenabled\x18\x06 \x01(\bR\aenabled\x12\x1f\n" +
+	"\vback_offset\x18\a \x01(\x04R\n" +
+	"backOffset\x12#\n" +
+	"\rloop_interval\x18\b \x01(\x04R\floopInterval\"\xd2\x01\n" +
+	"\vTokenConfig\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12!\n" +
+	"\fskeye_symbol\x18\x02 \x01(\tR\vskeyeSymbol\x12\x18\n" +
+	"\adecimal\x18\x03 \x01(\rR\adecimal\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12\x18\n" +
+	"\aaliases\x18\x05 \x03(\tR\aaliases\x12:\n" +
+	"\taddresses\x18\x06 \x03(\v2\x1c.cpchain.gasfee.TokenAddressR\taddresses\"M\n" +
+	"\x18UpsertChainConfigRequest\x121\n" +
+	"\x05chain\x18\x01 \x01(\v2\x1b.cpchain.gasfee.ChainConfigR\x05chain\"5\n" +
+	"\x18DeleteChainConfigRequest\x12\x19\n" +
+	"\bchain_id\x18\x01 \x01(\x04R\achainId\"\x18\n" +
+	"\x16ListChainConfigRequest\"\x89\x01\n" +
+	"\x17ListChainConfigResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x123\n" +
+	"\x06chains\x18\x03 \x03(\v2\x1b.cpchain.gasfee.ChainConfigR\x06chains\"M\n" +
+	"\x18UpsertTokenConfigRequest\x121\n" +
+	"\x05token\x18\x01 \x01(\v2\x1b.cpchain.gasfee.TokenConfigR\x05token\"2\n" +
+	"\x18DeleteTokenConfigRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\x18\n" +
+	"\x16ListTokenConfigRequest\"\x89\x01\n" +
+	"\x17ListTokenConfigResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x123\n" +
+	"\x06tokens\x18\x03 \x03(\v2\x1b.cpchain.gasfee.TokenConfigR\x06tokens\"J\n" +
+	"\rAdminResponse\x12\x1f\n" +
+	"\vreturn_code\x18\x01 \x01(\x04R\n" +
+	"returnCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xea\x02\n" +
 	"\x15TokenGasPriceServices\x12m\n" +
-	"\x1cgetTokenPriceAndGasByChainId\x12$.cpchain.gasfee.TokenGasPriceRequest\x1a%.cpchain.gasfee.TokenGasPriceResponse\"\x00B$\n" +
+	"\x1cgetTokenPriceAndGasByChainId\x12$.cpchain.gasfee.TokenGasPriceRequest\x1a%.cpchain.gasfee.TokenGasPriceResponse\"\x00\x12p\n" +
+	"\x13listSupportedChains\x12*.cpchain.gasfee.ListSupportedChainsRequest\x1a+.cpchain.gasfee.ListSupportedChainsResponse\"\x00\x12p\n" +
+	"\x13listSupportedTokens\x12*.cpchain.gasfee.ListSupportedTokensRequest\x1a+.cpchain.gasfee.ListSupportedTokensResponse\"\x002\xe4\x04\n" +
+	"\x16GasOracleAdminServices\x12^\n" +
+	"\x11upsertChainConfig\x12(.cpchain.gasfee.UpsertChainConfigRequest\x1a\x1d.cpchain.gasfee.AdminResponse\"\x00\x12^\n" +
+	"\x11deleteChainConfig\x12(.cpchain.gasfee.DeleteChainConfigRequest\x1a\x1d.cpchain.gasfee.AdminResponse\"\x00\x12d\n" +
+	"\x0flistChainConfig\x12&.cpchain.gasfee.ListChainConfigRequest\x1a'.cpchain.gasfee.ListChainConfigResponse\"\x00\x12^\n" +
+	"\x11upsertTokenConfig\x12(.cpchain.gasfee.UpsertTokenConfigRequest\x1a\x1d.cpchain.gasfee.AdminResponse\"\x00\x12^\n" +
+	"\x11deleteTokenConfig\x12(.cpchain.gasfee.DeleteTokenConfigRequest\x1a\x1d.cpchain.gasfee.AdminResponse\"\x00\x12d\n" +
+	"\x0flistTokenConfig\x12&.cpchain.gasfee.ListTokenConfigRequest\x1a'.cpchain.gasfee.ListTokenConfigResponse\"\x00B$\n" +
 	"\x12com.cpchain.gasfeeZ\x0e./proto/gasfeeb\x06proto3"
 
 var (
@@ -190,19 +1315,61 @@ func file_proto_gasfee_proto_rawDescGZIP() []byte {
 	return file_proto_gasfee_proto_rawDescData
 }
 
-var file_proto_gasfee_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_gasfee_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_gasfee_proto_goTypes = []any{
-	(*TokenGasPriceRequest)(nil),  // 0: cpchain.gasfee.TokenGasPriceRequest
-	(*TokenGasPriceResponse)(nil), // 1: cpchain.gasfee.TokenGasPriceResponse
+	(*TokenGasPriceRequest)(nil),        // 0: cpchain.gasfee.TokenGasPriceRequest
+	(*TokenGasPriceResponse)(nil),       // 1: cpchain.gasfee.TokenGasPriceResponse
+	(*ListSupportedChainsRequest)(nil),  // 2: cpchain.gasfee.ListSupportedChainsRequest
+	(*SupportedChain)(nil),              // 3: cpchain.gasfee.SupportedChain
+	(*ListSupportedChainsResponse)(nil), // 4: cpchain.gasfee.ListSupportedChainsResponse
+	(*ListSupportedTokensRequest)(nil),  // 5: cpchain.gasfee.ListSupportedTokensRequest
+	(*TokenAddress)(nil),                // 6: cpchain.gasfee.TokenAddress
+	(*SupportedToken)(nil),              // 7: cpchain.gasfee.SupportedToken
+	(*ListSupportedTokensResponse)(nil), // 8: cpchain.gasfee.ListSupportedTokensResponse
+	(*ChainConfig)(nil),                 // 9: cpchain.gasfee.ChainConfig
+	(*TokenConfig)(nil),                 // 10: cpchain.gasfee.TokenConfig
+	(*UpsertChainConfigRequest)(nil),    // 11: cpchain.gasfee.UpsertChainConfigRequest
+	(*DeleteChainConfigRequest)(nil),    // 12: cpchain.gasfee.DeleteChainConfigRequest
+	(*ListChainConfigRequest)(nil),      // 13: cpchain.gasfee.ListChainConfigRequest
+	(*ListChainConfigResponse)(nil),     // 14: cpchain.gasfee.ListChainConfigResponse
+	(*UpsertTokenConfigRequest)(nil),    // 15: cpchain.gasfee.UpsertTokenConfigRequest
+	(*DeleteTokenConfigRequest)(nil),    // 16: cpchain.gasfee.DeleteTokenConfigRequest
+	(*ListTokenConfigRequest)(nil),      // 17: cpchain.gasfee.ListTokenConfigRequest
+	(*ListTokenConfigResponse)(nil),     // 18: cpchain.gasfee.ListTokenConfigResponse
+	(*AdminResponse)(nil),               // 19: cpchain.gasfee.AdminResponse
 }
 var file_proto_gasfee_proto_depIdxs = []int32{
-	0, // 0: cpchain.gasfee.TokenGasPriceServices.getTokenPriceAndGasByChainId:input_type -> cpchain.gasfee.TokenGasPriceRequest
-	1, // 1: cpchain.gasfee.TokenGasPriceServices.getTokenPriceAndGasByChainId:output_type -> cpchain.gasfee.TokenGasPriceResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3,  // 0: cpchain.gasfee.ListSupportedChainsResponse.chains:type_name -> cpchain.gasfee.SupportedChain
+	6,  // 1: cpchain.gasfee.SupportedToken.addresses:type_name -> cpchain.gasfee.TokenAddress
+	7,  // 2: cpchain.gasfee.ListSupportedTokensResponse.tokens:type_name -> cpchain.gasfee.SupportedToken
+	6,  // 3: cpchain.gasfee.TokenConfig.addresses:type_name -> cpchain.gasfee.TokenAddress
+	9,  // 4: cpchain.gasfee.UpsertChainConfigRequest.chain:type_name -> cpchain.gasfee.ChainConfig
+	9,  // 5: cpchain.gasfee.ListChainConfigResponse.chains:type_name -> cpchain.gasfee.ChainConfig
+	10, // 6: cpchain.gasfee.UpsertTokenConfigRequest.token:type_name -> cpchain.gasfee.TokenConfig
+	10, // 7: cpchain.gasfee.ListTokenConfigResponse.tokens:type_name -> cpchain.gasfee.TokenConfig
+	0,  // 8: cpchain.gasfee.TokenGasPriceServices.getTokenPriceAndGasByChainId:input_type -> cpchain.gasfee.TokenGasPriceRequest
+	2,  // 9: cpchain.gasfee.TokenGasPriceServices.listSupportedChains:input_type -> cpchain.gasfee.ListSupportedChainsRequest
+	5,  // 10: cpchain.gasfee.TokenGasPriceServices.listSupportedTokens:input_type -> cpchain.gasfee.ListSupportedTokensRequest
+	11, // 11: cpchain.gasfee.GasOracleAdminServices.upsertChainConfig:input_type -> cpchain.gasfee.UpsertChainConfigRequest
+	12, // 12: cpchain.gasfee.GasOracleAdminServices.deleteChainConfig:input_type -> cpchain.gasfee.DeleteChainConfigRequest
+	13, // 13: cpchain.gasfee.GasOracleAdminServices.listChainConfig:input_type -> cpchain.gasfee.ListChainConfigRequest
+	15, // 14: cpchain.gasfee.GasOracleAdminServices.upsertTokenConfig:input_type -> cpchain.gasfee.UpsertTokenConfigRequest
+	16, // 15: cpchain.gasfee.GasOracleAdminServices.deleteTokenConfig:input_type -> cpchain.gasfee.DeleteTokenConfigRequest
+	17, // 16: cpchain.gasfee.GasOracleAdminServices.listTokenConfig:input_type -> cpchain.gasfee.ListTokenConfigRequest
+	1,  // 17: cpchain.gasfee.TokenGasPriceServices.getTokenPriceAndGasByChainId:output_type -> cpchain.gasfee.TokenGasPriceResponse
+	4,  // 18: cpchain.gasfee.TokenGasPriceServices.listSupportedChains:output_type -> cpchain.gasfee.ListSupportedChainsResponse
+	8,  // 19: cpchain.gasfee.TokenGasPriceServices.listSupportedTokens:output_type -> cpchain.gasfee.ListSupportedTokensResponse
+	19, // 20: cpchain.gasfee.GasOracleAdminServices.upsertChainConfig:output_type -> cpchain.gasfee.AdminResponse
+	19, // 21: cpchain.gasfee.GasOracleAdminServices.deleteChainConfig:output_type -> cpchain.gasfee.AdminResponse
+	14, // 22: cpchain.gasfee.GasOracleAdminServices.listChainConfig:output_type -> cpchain.gasfee.ListChainConfigResponse
+	19, // 23: cpchain.gasfee.GasOracleAdminServices.upsertTokenConfig:output_type -> cpchain.gasfee.AdminResponse
+	19, // 24: cpchain.gasfee.GasOracleAdminServices.deleteTokenConfig:output_type -> cpchain.gasfee.AdminResponse
+	18, // 25: cpchain.gasfee.GasOracleAdminServices.listTokenConfig:output_type -> cpchain.gasfee.ListTokenConfigResponse
+	17, // [17:26] is the sub-list for method output_type
+	8,  // [8:17] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_proto_gasfee_proto_init() }
@@ -216,9 +1383,9 @@ func file_proto_gasfee_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gasfee_proto_rawDesc), len(file_proto_gasfee_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   20,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_gasfee_proto_goTypes,
 		DependencyIndexes: file_proto_gasfee_proto_depIdxs,
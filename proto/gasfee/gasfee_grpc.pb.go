@@ -20,6 +20,8 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	TokenGasPriceServices_GetTokenPriceAndGasByChainId_FullMethodName = "/cpchain.gasfee.TokenGasPriceServices/getTokenPriceAndGasByChainId"
+	TokenGasPriceServices_ListSupportedChains_FullMethodName          = "/cpchain.gasfee.TokenGasPriceServices/listSupportedChains"
+	TokenGasPriceServices_ListSupportedTokens_FullMethodName          = "/cpchain.gasfee.TokenGasPriceServices/listSupportedTokens"
 )
 
 // TokenGasPriceServicesClient is the client API for TokenGasPriceServices service.
@@ -27,6 +29,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TokenGasPriceServicesClient interface {
 	GetTokenPriceAndGasByChainId(ctx context.Context, in *TokenGasPriceRequest, opts ...grpc.CallOption) (*TokenGasPriceResponse, error)
+	ListSupportedChains(ctx context.Context, in *ListSupportedChainsRequest, opts ...grpc.CallOption) (*ListSupportedChainsResponse, error)
+	ListSupportedTokens(ctx context.Context, in *ListSupportedTokensRequest, opts ...grpc.CallOption) (*ListSupportedTokensResponse, error)
 }
 
 type tokenGasPriceServicesClient struct {
@@ -47,11 +51,33 @@ func (c *tokenGasPriceServicesClient) GetTokenPriceAndGasByChainId(ctx context.C
 	return out, nil
 }
 
+func (c *tokenGasPriceServicesClient) ListSupportedChains(ctx context.Context, in *ListSupportedChainsRequest, opts ...grpc.CallOption) (*ListSupportedChainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSupportedChainsResponse)
+	err := c.cc.Invoke(ctx, TokenGasPriceServices_ListSupportedChains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenGasPriceServicesClient) ListSupportedTokens(ctx context.Context, in *ListSupportedTokensRequest, opts ...grpc.CallOption) (*ListSupportedTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSupportedTokensResponse)
+	err := c.cc.Invoke(ctx, TokenGasPriceServices_ListSupportedTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TokenGasPriceServicesServer is the server API for TokenGasPriceServices service.
 // All implementations should embed UnimplementedTokenGasPriceServicesServer
 // for forward compatibility.
 type TokenGasPriceServicesServer interface {
 	GetTokenPriceAndGasByChainId(context.Context, *TokenGasPriceRequest) (*TokenGasPriceResponse, error)
+	ListSupportedChains(context.Context, *ListSupportedChainsRequest) (*ListSupportedChainsResponse, error)
+	ListSupportedTokens(context.Context, *ListSupportedTokensRequest) (*ListSupportedTokensResponse, error)
 }
 
 // UnimplementedTokenGasPriceServicesServer should be embedded to have
@@ -64,6 +90,12 @@ type UnimplementedTokenGasPriceServicesServer struct{}
 func (UnimplementedTokenGasPriceServicesServer) GetTokenPriceAndGasByChainId(context.Context, *TokenGasPriceRequest) (*TokenGasPriceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTokenPriceAndGasByChainId not implemented")
 }
+func (UnimplementedTokenGasPriceServicesServer) ListSupportedChains(context.Context, *ListSupportedChainsRequest) (*ListSupportedChainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSupportedChains not implemented")
+}
+func (UnimplementedTokenGasPriceServicesServer) ListSupportedTokens(context.Context, *ListSupportedTokensRequest) (*ListSupportedTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSupportedTokens not implemented")
+}
 func (UnimplementedTokenGasPriceServicesServer) testEmbeddedByValue() {}
 
 // UnsafeTokenGasPriceServicesServer may be embedded to opt out of forward compatibility for this service.
@@ -102,6 +134,42 @@ func _TokenGasPriceServices_GetTokenPriceAndGasByChainId_Handler(srv interface{}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TokenGasPriceServices_ListSupportedChains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSupportedChainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenGasPriceServicesServer).ListSupportedChains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenGasPriceServices_ListSupportedChains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenGasPriceServicesServer).ListSupportedChains(ctx, req.(*ListSupportedChainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenGasPriceServices_ListSupportedTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSupportedTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenGasPriceServicesServer).ListSupportedTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TokenGasPriceServices_ListSupportedTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenGasPriceServicesServer).ListSupportedTokens(ctx, req.(*ListSupportedTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TokenGasPriceServices_ServiceDesc is the grpc.ServiceDesc for TokenGasPriceServices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -113,6 +181,304 @@ var TokenGasPriceServices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "getTokenPriceAndGasByChainId",
 			Handler:    _TokenGasPriceServices_GetTokenPriceAndGasByChainId_Handler,
 		},
+		{
+			MethodName: "listSupportedChains",
+			Handler:    _TokenGasPriceServices_ListSupportedChains_Handler,
+		},
+		{
+			MethodName: "listSupportedTokens",
+			Handler:    _TokenGasPriceServices_ListSupportedTokens_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/gasfee.proto",
+}
+
+const (
+	GasOracleAdminServices_UpsertChainConfig_FullMethodName = "/cpchain.gasfee.GasOracleAdminServices/upsertChainConfig"
+	GasOracleAdminServices_DeleteChainConfig_FullMethodName = "/cpchain.gasfee.GasOracleAdminServices/deleteChainConfig"
+	GasOracleAdminServices_ListChainConfig_FullMethodName   = "/cpchain.gasfee.GasOracleAdminServices/listChainConfig"
+	GasOracleAdminServices_UpsertTokenConfig_FullMethodName = "/cpchain.gasfee.GasOracleAdminServices/upsertTokenConfig"
+	GasOracleAdminServices_DeleteTokenConfig_FullMethodName = "/cpchain.gasfee.GasOracleAdminServices/deleteTokenConfig"
+	GasOracleAdminServices_ListTokenConfig_FullMethodName   = "/cpchain.gasfee.GasOracleAdminServices/listTokenConfig"
+)
+
+// GasOracleAdminServicesClient is the client API for GasOracleAdminServices service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GasOracleAdminServicesClient interface {
+	UpsertChainConfig(ctx context.Context, in *UpsertChainConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	DeleteChainConfig(ctx context.Context, in *DeleteChainConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	ListChainConfig(ctx context.Context, in *ListChainConfigRequest, opts ...grpc.CallOption) (*ListChainConfigResponse, error)
+	UpsertTokenConfig(ctx context.Context, in *UpsertTokenConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	DeleteTokenConfig(ctx context.Context, in *DeleteTokenConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	ListTokenConfig(ctx context.Context, in *ListTokenConfigRequest, opts ...grpc.CallOption) (*ListTokenConfigResponse, error)
+}
+
+type gasOracleAdminServicesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGasOracleAdminServicesClient(cc grpc.ClientConnInterface) GasOracleAdminServicesClient {
+	return &gasOracleAdminServicesClient{cc}
+}
+
+func (c *gasOracleAdminServicesClient) UpsertChainConfig(ctx context.Context, in *UpsertChainConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_UpsertChainConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gasOracleAdminServicesClient) DeleteChainConfig(ctx context.Context, in *DeleteChainConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_DeleteChainConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gasOracleAdminServicesClient) ListChainConfig(ctx context.Context, in *ListChainConfigRequest, opts ...grpc.CallOption) (*ListChainConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChainConfigResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_ListChainConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gasOracleAdminServicesClient) UpsertTokenConfig(ctx context.Context, in *UpsertTokenConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_UpsertTokenConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gasOracleAdminServicesClient) DeleteTokenConfig(ctx context.Context, in *DeleteTokenConfigRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_DeleteTokenConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gasOracleAdminServicesClient) ListTokenConfig(ctx context.Context, in *ListTokenConfigRequest, opts ...grpc.CallOption) (*ListTokenConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTokenConfigResponse)
+	err := c.cc.Invoke(ctx, GasOracleAdminServices_ListTokenConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GasOracleAdminServicesServer is the server API for GasOracleAdminServices service.
+// All implementations should embed UnimplementedGasOracleAdminServicesServer
+// for forward compatibility.
+type GasOracleAdminServicesServer interface {
+	UpsertChainConfig(context.Context, *UpsertChainConfigRequest) (*AdminResponse, error)
+	DeleteChainConfig(context.Context, *DeleteChainConfigRequest) (*AdminResponse, error)
+	ListChainConfig(context.Context, *ListChainConfigRequest) (*ListChainConfigResponse, error)
+	UpsertTokenConfig(context.Context, *UpsertTokenConfigRequest) (*AdminResponse, error)
+	DeleteTokenConfig(context.Context, *DeleteTokenConfigRequest) (*AdminResponse, error)
+	ListTokenConfig(context.Context, *ListTokenConfigRequest) (*ListTokenConfigResponse, error)
+}
+
+// UnimplementedGasOracleAdminServicesServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGasOracleAdminServicesServer struct{}
+
+func (UnimplementedGasOracleAdminServicesServer) UpsertChainConfig(context.Context, *UpsertChainConfigRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertChainConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) DeleteChainConfig(context.Context, *DeleteChainConfigRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChainConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) ListChainConfig(context.Context, *ListChainConfigRequest) (*ListChainConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChainConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) UpsertTokenConfig(context.Context, *UpsertTokenConfigRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertTokenConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) DeleteTokenConfig(context.Context, *DeleteTokenConfigRequest) (*AdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTokenConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) ListTokenConfig(context.Context, *ListTokenConfigRequest) (*ListTokenConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTokenConfig not implemented")
+}
+func (UnimplementedGasOracleAdminServicesServer) testEmbeddedByValue() {}
+
+// UnsafeGasOracleAdminServicesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GasOracleAdminServicesServer will
+// result in compilation errors.
+type UnsafeGasOracleAdminServicesServer interface {
+	mustEmbedUnimplementedGasOracleAdminServicesServer()
+}
+
+func RegisterGasOracleAdminServicesServer(s grpc.ServiceRegistrar, srv GasOracleAdminServicesServer) {
+	// If the following call pancis, it indicates UnimplementedGasOracleAdminServicesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GasOracleAdminServices_ServiceDesc, srv)
+}
+
+func _GasOracleAdminServices_UpsertChainConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertChainConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).UpsertChainConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_UpsertChainConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).UpsertChainConfig(ctx, req.(*UpsertChainConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GasOracleAdminServices_DeleteChainConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChainConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).DeleteChainConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_DeleteChainConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).DeleteChainConfig(ctx, req.(*DeleteChainConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GasOracleAdminServices_ListChainConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChainConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).ListChainConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_ListChainConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).ListChainConfig(ctx, req.(*ListChainConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GasOracleAdminServices_UpsertTokenConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertTokenConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).UpsertTokenConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_UpsertTokenConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).UpsertTokenConfig(ctx, req.(*UpsertTokenConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GasOracleAdminServices_DeleteTokenConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTokenConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).DeleteTokenConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_DeleteTokenConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).DeleteTokenConfig(ctx, req.(*DeleteTokenConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GasOracleAdminServices_ListTokenConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTokenConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GasOracleAdminServicesServer).ListTokenConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GasOracleAdminServices_ListTokenConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GasOracleAdminServicesServer).ListTokenConfig(ctx, req.(*ListTokenConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GasOracleAdminServices_ServiceDesc is the grpc.ServiceDesc for GasOracleAdminServices service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GasOracleAdminServices_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cpchain.gasfee.GasOracleAdminServices",
+	HandlerType: (*GasOracleAdminServicesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "upsertChainConfig",
+			Handler:    _GasOracleAdminServices_UpsertChainConfig_Handler,
+		},
+		{
+			MethodName: "deleteChainConfig",
+			Handler:    _GasOracleAdminServices_DeleteChainConfig_Handler,
+		},
+		{
+			MethodName: "listChainConfig",
+			Handler:    _GasOracleAdminServices_ListChainConfig_Handler,
+		},
+		{
+			MethodName: "upsertTokenConfig",
+			Handler:    _GasOracleAdminServices_UpsertTokenConfig_Handler,
+		},
+		{
+			MethodName: "deleteTokenConfig",
+			Handler:    _GasOracleAdminServices_DeleteTokenConfig_Handler,
+		},
+		{
+			MethodName: "listTokenConfig",
+			Handler:    _GasOracleAdminServices_ListTokenConfig_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/gasfee.proto",
@@ -0,0 +1,278 @@
+package gas_oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/common/symbols"
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/synchronizer"
+	"github.com/cpchain-network/gas-oracle/synchronizer/node"
+)
+
+// configCheckInterval bounds how often the config file is stat'ed for changes.
+const configCheckInterval = 5 * time.Second
+
+// registryCheckInterval is how often chains and tokens managed in the database
+// are reloaded.
+const registryCheckInterval = 30 * time.Second
+
+// WatchConfig makes the oracle reload its chains and symbols from path when
+// the file changes or the process receives SIGHUP, and from the database
+// registry periodically. It must be called before Start.
+func (as *GasOracle) WatchConfig(path string) {
+	as.configPath = path
+}
+
+func (as *GasOracle) watchConfig(path string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	as.reloadCancel = cancel
+	as.reloadDone = make(chan struct{})
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	modTime := configModTime(path)
+
+	go func() {
+		defer close(as.reloadDone)
+		defer signal.Stop(hup)
+		ticker := time.NewTicker(configCheckInterval)
+		defer ticker.Stop()
+		registryTicker := time.NewTicker(registryCheckInterval)
+		defer registryTicker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-registryTicker.C:
+				log.Debug("reloading chain and token registry")
+			case <-hup:
+				log.Info("received SIGHUP, reloading config", "path", path)
+			case <-ticker.C:
+				current := configModTime(path)
+				// a file is briefly missing while it is being replaced
+				if current.IsZero() || current.Equal(modTime) {
+					continue
+				}
+				log.Info("config file changed, reloading config", "path", path)
+			}
+			modTime = configModTime(path)
+			if err := as.Reload(ctx, path); err != nil {
+				log.Error("reload config fail, keep running the previous config", "err", err)
+			}
+		}
+	}()
+}
+
+func configModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Reload loads the config at path, merges the database registry into it and
+// applies the changed chains and symbols: synchronizers of removed chains are
+// stopped, those of added or changed chains are started and the worker picks
+// up the new symbol list. Other settings only take effect on restart.
+//
+// Chains are stopped and started without holding mu, so leases are acquired
+// and lost while a synchronizer finishes its round.
+func (as *GasOracle) Reload(ctx context.Context, path string) error {
+	fileCfg, err := config.New(path)
+	if err != nil {
+		return err
+	}
+	cfg, err := as.applyRegistry(fileCfg)
+	if err != nil {
+		return err
+	}
+
+	as.reloadMu.Lock()
+	defer as.reloadMu.Unlock()
+	removed, added, ok := as.applyReload(cfg)
+	if !ok {
+		return nil
+	}
+
+	var result error
+	for _, chain := range removed {
+		if err := chain.stop(ctx); err != nil {
+			result = errors.Join(result, err)
+		}
+	}
+	for _, rpc := range added {
+		log.Info("starting synchronizer", "chainId", rpc.ChainId, "nativeToken", rpc.NativeToken, "decimal", rpc.Decimal)
+		if err := as.startChain(ctx, rpc); err != nil {
+			result = errors.Join(result, fmt.Errorf("failed to start chain %d: %w", rpc.ChainId, err))
+		}
+	}
+	return result
+}
+
+// applyReload switches to cfg under mu. It detaches the chains that were
+// removed or changed, for the caller to stop, and returns the chains to start.
+func (as *GasOracle) applyReload(cfg *config.Config) ([]detachedChain, []config.RPC, bool) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if as.stopped.Load() {
+		return nil, nil, false
+	}
+
+	as.warnStaticChanges(cfg)
+	// chains started below name their native token by the new symbols
+	as.symbolMap = symbols.NewMap(cfg.Symbols)
+
+	next := make(map[uint64]config.RPC, len(cfg.RPCs))
+	for _, rpc := range cfg.RPCs {
+		next[rpc.ChainId] = *rpc
+	}
+
+	var removed []detachedChain
+	for _, chainId := range slices.Clone(as.chainIdList) {
+		rpc, ok := next[chainId]
+		if ok && reflect.DeepEqual(rpc, as.rpcs[chainId]) {
+			continue
+		}
+		if ok {
+			log.Info("chain config changed, restarting synchronizer", "chainId", chainId, "nativeToken", rpc.NativeToken, "decimal", rpc.Decimal)
+		} else {
+			log.Info("chain removed from config, stopping synchronizer", "chainId", chainId)
+		}
+		removed = append(removed, as.detachChain(chainId))
+	}
+	var added []config.RPC
+	for _, rpc := range cfg.RPCs {
+		if _, ok := as.rpcs[rpc.ChainId]; !ok {
+			added = append(added, *rpc)
+		}
+	}
+
+	as.reloadSymbols(as.cfg.Symbols, cfg.Symbols)
+	as.cfg = cfg
+	return removed, added, true
+}
+
+// startChain dials rpc without holding mu and registers the chain, starting
+// its synchronizer unless another replica holds its lease.
+func (as *GasOracle) startChain(ctx context.Context, rpc config.RPC) error {
+	client, err := as.dialRPCClient(ctx, rpc)
+	if err != nil {
+		return err
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if as.stopped.Load() {
+		client.Close()
+		return nil
+	}
+	as.addRPCClient(rpc, client)
+	// under leader election the elector starts new chains once it holds their
+	// lease; a changed chain keeps running when its lease is still held
+	if as.elector != nil && !as.elector.Holds(chainLeaseKey(rpc.ChainId)) {
+		return nil
+	}
+	if err := as.initChainSynchronizer(rpc); err != nil {
+		return errors.Join(err, as.detachChain(rpc.ChainId).stop(ctx))
+	}
+	if err := as.synchronizer[rpc.ChainId].Start(context.Background()); err != nil {
+		return errors.Join(err, as.detachChain(rpc.ChainId).stop(ctx))
+	}
+	return nil
+}
+
+// detachedChain is a chain removed from the oracle whose synchronizer and
+// client still have to be stopped.
+type detachedChain struct {
+	chainId uint64
+	syncer  *synchronizer.OracleSynchronizer
+	client  node.EthClient
+}
+
+// detachChain removes chainId from the per chain state. The caller holds mu.
+func (as *GasOracle) detachChain(chainId uint64) detachedChain {
+	chain := detachedChain{chainId: chainId, syncer: as.synchronizer[chainId]}
+	delete(as.synchronizer, chainId)
+	as.clientMu.Lock()
+	chain.client = as.ethClient[chainId]
+	delete(as.ethClient, chainId)
+	as.clientMu.Unlock()
+	delete(as.rpcs, chainId)
+	as.chainIdList = slices.DeleteFunc(as.chainIdList, func(id uint64) bool {
+		return id == chainId
+	})
+	return chain
+}
+
+func (chain detachedChain) stop(ctx context.Context) error {
+	var result error
+	if chain.syncer != nil {
+		if err := chain.syncer.Stop(ctx); err != nil {
+			result = fmt.Errorf("failed to close synchronizer %d: %w", chain.chainId, err)
+		}
+	}
+	if chain.client != nil {
+		chain.client.Close()
+	}
+	return result
+}
+
+func (as *GasOracle) reloadSymbols(previous []config.Symbols, symbolList []config.Symbols) {
+	current := make(map[string]config.Symbols)
+	for _, symbol := range previous {
+		current[symbol.Name] = symbol
+	}
+	changed := len(current) != len(symbolList)
+	for _, symbol := range symbolList {
+		old, ok := current[symbol.Name]
+		switch {
+		case !ok:
+			log.Info("symbol added to config", "symbol", symbol.Name, "decimal", symbol.Decimal)
+			changed = true
+		case !reflect.DeepEqual(old, symbol):
+			log.Info("symbol config changed", "symbol", symbol.Name, "decimal", symbol.Decimal)
+			changed = true
+		}
+		delete(current, symbol.Name)
+	}
+	for name := range current {
+		log.Info("symbol removed from config", "symbol", name)
+	}
+	// without the worker lease the handle is created from the new config once acquired
+	if changed && as.workerHandle != nil {
+		as.workerHandle.SetSymbolList(workerSymbols(symbolList), as.symbolMap)
+	}
+}
+
+// warnStaticChanges logs settings that changed but are only read on startup.
+func (as *GasOracle) warnStaticChanges(cfg *config.Config) {
+	static := map[string][2]interface{}{
+		"loop_internal":           {as.cfg.LoopInternal, cfg.LoopInternal},
+		"back_offset":             {as.cfg.BackOffset, cfg.BackOffset},
+		"skyeye_url":              {as.cfg.SkyeyeUrl, cfg.SkyeyeUrl},
+		"quote_currencies":        {as.cfg.QuoteCurrencies, cfg.QuoteCurrencies},
+		"dex_pools":               {as.cfg.DexPools, cfg.DexPools},
+		"chainlink_feeds":         {as.cfg.ChainlinkFeeds, cfg.ChainlinkFeeds},
+		"price_guard":             {as.cfg.PriceGuard, cfg.PriceGuard},
+		"price_loop_interval":     {as.cfg.PriceLoopInterval, cfg.PriceLoopInterval},
+		"price_fetch_concurrency": {as.cfg.PriceFetchConcurrency, cfg.PriceFetchConcurrency},
+		"price_request_timeout":   {as.cfg.PriceRequestTimeout, cfg.PriceRequestTimeout},
+		"master_db":               {as.cfg.MasterDb, cfg.MasterDb},
+	}
+	for name, values := range static {
+		if !reflect.DeepEqual(values[0], values[1]) {
+			log.Warn("config change needs a restart to take effect", "field", name)
+		}
+	}
+}
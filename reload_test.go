@@ -0,0 +1,105 @@
+package gas_oracle
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// writeReloadConfig writes a config sampling one chain per entry of chains,
+// which maps the chain id to its decimal, every hour so no round runs.
+func writeReloadConfig(t *testing.T, path string, rpcUrl string, chains map[uint64]uint8) {
+	var rpcs strings.Builder
+	for _, chainId := range slices.Sorted(maps.Keys(chains)) {
+		fmt.Fprintf(&rpcs, "  - rpc_url: %s\n    chain_id: %d\n    native_token: eth\n    decimal: %d\n", rpcUrl, chainId, chains[chainId])
+	}
+	require.NoError(t, os.WriteFile(path, []byte(`
+back_offset: 2
+loop_internal: 1h
+price_loop_interval: 1h
+skyeye_url: `+rpcUrl+`
+symbols:
+  - name: eth
+    decimal: 18
+rpcs:
+`+rpcs.String()), 0600))
+}
+
+func newReloadOracle(t *testing.T, chains map[uint64]uint8) (*GasOracle, string, string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "gas-oracle.yaml")
+	writeReloadConfig(t, path, server.URL, chains)
+	cfg, err := config.New(path)
+	require.NoError(t, err)
+
+	as, err := NewGasOracleFromDB(context.Background(), cfg, database.NewMemoryDB(), nil, func(error) {})
+	require.NoError(t, err)
+	require.NoError(t, as.Start(context.Background()))
+	t.Cleanup(func() {
+		require.NoError(t, as.Stop(context.Background()))
+	})
+	return as, path, server.URL
+}
+
+func runningChains(as *GasOracle) []uint64 {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	chains := slices.Clone(as.chainIdList)
+	slices.Sort(chains)
+	for _, chainId := range chains {
+		if as.synchronizer[chainId] == nil || as.ethClient[chainId] == nil {
+			return nil
+		}
+	}
+	return chains
+}
+
+func TestReloadAddsAndRemovesChains(t *testing.T) {
+	as, path, url := newReloadOracle(t, map[uint64]uint8{1: 18, 10: 18})
+	require.Equal(t, []uint64{1, 10}, runningChains(as))
+	removed := as.synchronizer[10]
+
+	writeReloadConfig(t, path, url, map[uint64]uint8{1: 18, 56: 18})
+	require.NoError(t, as.Reload(context.Background(), path))
+	require.Equal(t, []uint64{1, 56}, runningChains(as))
+	require.True(t, removed.Stopped(), "the removed chain is stopped")
+	require.Equal(t, []string{"chain:1", "chain:56", "worker"}, as.LeaseKeys())
+}
+
+func TestReloadRestartsChangedChains(t *testing.T) {
+	as, path, url := newReloadOracle(t, map[uint64]uint8{1: 18, 10: 18})
+	unchanged, changed := as.synchronizer[1], as.synchronizer[10]
+
+	writeReloadConfig(t, path, url, map[uint64]uint8{1: 18, 10: 9})
+	require.NoError(t, as.Reload(context.Background(), path))
+	require.Equal(t, []uint64{1, 10}, runningChains(as))
+	require.Same(t, unchanged, as.synchronizer[1], "an unchanged chain keeps running")
+	require.False(t, unchanged.Stopped())
+	require.NotSame(t, changed, as.synchronizer[10])
+	require.True(t, changed.Stopped())
+	require.Equal(t, uint8(9), as.rpcs[10].Decimal)
+}
+
+func TestReloadFailureKeepsRunningChains(t *testing.T) {
+	as, path, _ := newReloadOracle(t, map[uint64]uint8{1: 18})
+
+	require.NoError(t, os.WriteFile(path, []byte("loop_internal: [\n"), 0600))
+	require.Error(t, as.Reload(context.Background(), path))
+	require.Equal(t, []uint64{1}, runningChains(as))
+}
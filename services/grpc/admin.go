@@ -0,0 +1,285 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/proto/gasfee"
+)
+
+// AdminTokenMetadataKey carries the admin token for the admin service.
+const AdminTokenMetadataKey = "admin-token"
+
+var adminMethodPrefix = "/" + gasfee.GasOracleAdminServices_ServiceDesc.ServiceName + "/"
+
+func isAdminMethod(method string) bool {
+	return strings.HasPrefix(method, adminMethodPrefix)
+}
+
+// AdminAuth guards the admin service with the tokens from the admin config.
+// Other methods pass through untouched.
+type AdminAuth struct {
+	tokens []string
+}
+
+func NewAdminAuth(conf config.Admin) *AdminAuth {
+	return &AdminAuth{tokens: conf.Tokens}
+}
+
+func (aa *AdminAuth) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !isAdminMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(AdminTokenMetadataKey); len(values) > 0 {
+			token = values[0]
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing admin token")
+	}
+	for _, adminToken := range aa.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+			log.Info("admin request", "method", info.FullMethod)
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.PermissionDenied, "invalid admin token")
+}
+
+// AdminService manages the chain and token registry the oracle loads on top
+// of its yaml config.
+type AdminService struct {
+	db  *database.DB
+	cfg *config.Config
+
+	gasfee.UnimplementedGasOracleAdminServicesServer
+}
+
+// NewAdminService manages the registry merged into cfg, the validated yaml
+// config, and rejects rows that would leave the merged config invalid.
+func NewAdminService(db *database.DB, cfg *config.Config) *AdminService {
+	return &AdminService{db: db, cfg: cfg}
+}
+
+func (as *AdminService) UpsertChainConfig(ctx context.Context, in *gasfee.UpsertChainConfigRequest) (*gasfee.AdminResponse, error) {
+	chain := in.GetChain()
+	switch {
+	case chain == nil || chain.ChainId == 0:
+		return nil, status.Error(codes.InvalidArgument, "chain id is required")
+	case len(chain.RpcUrls) == 0 || chain.RpcUrls[0] == "":
+		return nil, status.Error(codes.InvalidArgument, "at least one rpc url is required")
+	case chain.NativeToken == "":
+		return nil, status.Error(codes.InvalidArgument, "native token is required")
+	case chain.Decimal > 255:
+		return nil, status.Error(codes.InvalidArgument, "decimal out of range")
+	}
+	chainConfig := &database.ChainConfig{
+		ChainId:      new(big.Int).SetUint64(chain.ChainId),
+		ChainName:    chain.ChainName,
+		RpcUrls:      chain.RpcUrls,
+		NativeToken:  chain.NativeToken,
+		Decimal:      uint8(chain.Decimal),
+		Enabled:      chain.Enabled,
+		BackOffset:   chain.BackOffset,
+		LoopInterval: chain.LoopInterval,
+		Timestamp:    uint64(time.Now().Unix()),
+	}
+	chainConfigList, tokenConfigList, err := as.registryRows()
+	if err != nil {
+		return nil, err
+	}
+	chainConfigList = append(withoutChain(chainConfigList, chainConfig.ChainId), *chainConfig)
+	if err := as.validateRegistry(chainConfigList, tokenConfigList, codes.InvalidArgument); err != nil {
+		return nil, err
+	}
+	if err := as.db.ChainConfig.StoreOrUpdateChainConfig(chainConfig); err != nil {
+		return nil, status.Error(codes.Internal, "store chain config fail")
+	}
+	log.Info("chain config stored", "chainId", chain.ChainId, "enabled", chain.Enabled)
+	return &gasfee.AdminResponse{ReturnCode: 100, Message: "store chain config success"}, nil
+}
+
+func (as *AdminService) DeleteChainConfig(ctx context.Context, in *gasfee.DeleteChainConfigRequest) (*gasfee.AdminResponse, error) {
+	chainId := new(big.Int).SetUint64(in.ChainId)
+	chainConfigList, tokenConfigList, err := as.registryRows()
+	if err != nil {
+		return nil, err
+	}
+	if err := as.validateRegistry(withoutChain(chainConfigList, chainId), tokenConfigList, codes.FailedPrecondition); err != nil {
+		return nil, err
+	}
+	deleted, err := as.db.ChainConfig.DeleteChainConfig(chainId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "delete chain config fail")
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "chain config not found")
+	}
+	log.Info("chain config deleted", "chainId", in.ChainId)
+	return &gasfee.AdminResponse{ReturnCode: 100, Message: "delete chain config success"}, nil
+}
+
+func (as *AdminService) ListChainConfig(ctx context.Context, in *gasfee.ListChainConfigRequest) (*gasfee.ListChainConfigResponse, error) {
+	chainConfigList, err := as.db.ChainConfig.QueryChainConfigList()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "query chain config fail")
+	}
+	chains := make([]*gasfee.ChainConfig, 0, len(chainConfigList))
+	for _, chainConfig := range chainConfigList {
+		chains = append(chains, &gasfee.ChainConfig{
+			ChainId:      chainConfig.ChainId.Uint64(),
+			ChainName:    chainConfig.ChainName,
+			RpcUrls:      chainConfig.RpcUrls,
+			NativeToken:  chainConfig.NativeToken,
+			Decimal:      uint32(chainConfig.Decimal),
+			Enabled:      chainConfig.Enabled,
+			BackOffset:   chainConfig.BackOffset,
+			LoopInterval: chainConfig.LoopInterval,
+		})
+	}
+	return &gasfee.ListChainConfigResponse{ReturnCode: 100, Message: "list chain config success", Chains: chains}, nil
+}
+
+func (as *AdminService) UpsertTokenConfig(ctx context.Context, in *gasfee.UpsertTokenConfigRequest) (*gasfee.AdminResponse, error) {
+	token := in.GetToken()
+	switch {
+	case token == nil || token.Symbol == "":
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	case token.Decimal > 255:
+		return nil, status.Error(codes.InvalidArgument, "decimal out of range")
+	case slices.Contains(token.Aliases, ""):
+		return nil, status.Error(codes.InvalidArgument, "aliases must not be empty")
+	}
+	addresses := make(map[uint64]string, len(token.Addresses))
+	for _, address := range token.Addresses {
+		switch {
+		case address.GetChainId() == 0:
+			return nil, status.Error(codes.InvalidArgument, "token address chain id is required")
+		case !common.IsHexAddress(address.GetAddress()):
+			return nil, status.Errorf(codes.InvalidArgument, "token address %q is not a hex address", address.GetAddress())
+		case addresses[address.ChainId] != "":
+			return nil, status.Errorf(codes.InvalidArgument, "token has two addresses on chain %d", address.ChainId)
+		}
+		addresses[address.ChainId] = address.Address
+	}
+	tokenConfig := &database.TokenConfig{
+		Symbol:      token.Symbol,
+		SkeyeSymbol: token.SkeyeSymbol,
+		Aliases:     token.Aliases,
+		Addresses:   addresses,
+		Decimal:     uint8(token.Decimal),
+		Enabled:     token.Enabled,
+		Timestamp:   uint64(time.Now().Unix()),
+	}
+	chainConfigList, tokenConfigList, err := as.registryRows()
+	if err != nil {
+		return nil, err
+	}
+	tokenConfigList = append(withoutToken(tokenConfigList, tokenConfig.Symbol), *tokenConfig)
+	if err := as.validateRegistry(chainConfigList, tokenConfigList, codes.InvalidArgument); err != nil {
+		return nil, err
+	}
+	if err := as.db.TokenConfig.StoreOrUpdateTokenConfig(tokenConfig); err != nil {
+		return nil, status.Error(codes.Internal, "store token config fail")
+	}
+	log.Info("token config stored", "symbol", token.Symbol, "enabled", token.Enabled)
+	return &gasfee.AdminResponse{ReturnCode: 100, Message: "store token config success"}, nil
+}
+
+func (as *AdminService) DeleteTokenConfig(ctx context.Context, in *gasfee.DeleteTokenConfigRequest) (*gasfee.AdminResponse, error) {
+	chainConfigList, tokenConfigList, err := as.registryRows()
+	if err != nil {
+		return nil, err
+	}
+	if err := as.validateRegistry(chainConfigList, withoutToken(tokenConfigList, in.Symbol), codes.FailedPrecondition); err != nil {
+		return nil, err
+	}
+	deleted, err := as.db.TokenConfig.DeleteTokenConfig(in.Symbol)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "delete token config fail")
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "token config not found")
+	}
+	log.Info("token config deleted", "symbol", in.Symbol)
+	return &gasfee.AdminResponse{ReturnCode: 100, Message: "delete token config success"}, nil
+}
+
+func (as *AdminService) ListTokenConfig(ctx context.Context, in *gasfee.ListTokenConfigRequest) (*gasfee.ListTokenConfigResponse, error) {
+	tokenConfigList, err := as.db.TokenConfig.QueryTokenConfigList()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "query token config fail")
+	}
+	tokens := make([]*gasfee.TokenConfig, 0, len(tokenConfigList))
+	for _, tokenConfig := range tokenConfigList {
+		tokens = append(tokens, &gasfee.TokenConfig{
+			Symbol:      tokenConfig.Symbol,
+			SkeyeSymbol: tokenConfig.SkeyeSymbol,
+			Decimal:     uint32(tokenConfig.Decimal),
+			Enabled:     tokenConfig.Enabled,
+			Aliases:     tokenConfig.Aliases,
+			Addresses:   tokenAddresses(tokenConfig.TokenAddresses()),
+		})
+	}
+	return &gasfee.ListTokenConfigResponse{ReturnCode: 100, Message: "list token config success", Tokens: tokens}, nil
+}
+
+// registryRows returns the rows of the registry tables.
+func (as *AdminService) registryRows() ([]database.ChainConfig, []database.TokenConfig, error) {
+	chainConfigList, err := as.db.ChainConfig.QueryChainConfigList()
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "query chain config fail")
+	}
+	tokenConfigList, err := as.db.TokenConfig.QueryTokenConfigList()
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "query token config fail")
+	}
+	return chainConfigList, tokenConfigList, nil
+}
+
+// validateRegistry merges the registry rows a request would leave into the
+// yaml config and validates the result, so the oracle never has to skip a
+// row. An invalid result is rejected with code.
+func (as *AdminService) validateRegistry(chainConfigList []database.ChainConfig, tokenConfigList []database.TokenConfig, code codes.Code) error {
+	merged := *as.cfg
+	database.MergeRegistry(&merged, chainConfigList, tokenConfigList)
+	if err := merged.Validate(); err != nil {
+		return status.Errorf(code, "registry would be invalid: %v", err)
+	}
+	return nil
+}
+
+func withoutChain(chainConfigList []database.ChainConfig, chainId *big.Int) []database.ChainConfig {
+	return slices.DeleteFunc(chainConfigList, func(c database.ChainConfig) bool {
+		return c.ChainId.Cmp(chainId) == 0
+	})
+}
+
+func withoutToken(tokenConfigList []database.TokenConfig, symbol string) []database.TokenConfig {
+	return slices.DeleteFunc(tokenConfigList, func(c database.TokenConfig) bool {
+		return c.Symbol == symbol
+	})
+}
+
+func tokenAddresses(addresses []config.TokenAddress) []*gasfee.TokenAddress {
+	tokenAddresses := make([]*gasfee.TokenAddress, 0, len(addresses))
+	for _, address := range addresses {
+		tokenAddresses = append(tokenAddresses, &gasfee.TokenAddress{ChainId: address.ChainId, Address: address.Address})
+	}
+	return tokenAddresses
+}
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/proto/gasfee"
+)
+
+func newTestAdmin(t *testing.T) (*AdminService, *database.DB) {
+	db := database.NewMemoryDB()
+	cfg := &config.Config{
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		SkyeyeUrl:    "http://skyeye",
+		Symbols:      []config.Symbols{{Name: "eth", Decimal: 18}},
+		RPCs:         []*config.RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH", Decimal: 18}},
+	}
+	require.NoError(t, cfg.Validate())
+	return NewAdminService(db, cfg), db
+}
+
+func TestUpsertChainConfigValidatesMergedConfig(t *testing.T) {
+	as, db := newTestAdmin(t)
+	ctx := context.Background()
+
+	_, err := as.UpsertChainConfig(ctx, &gasfee.UpsertChainConfigRequest{Chain: &gasfee.ChainConfig{
+		ChainId: 56, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18, Enabled: true,
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.ErrorContains(t, err, `native_token BNB has no matching entry "bnb" in symbols`)
+	chainConfigList, err := db.ChainConfig.QueryChainConfigList()
+	require.NoError(t, err)
+	require.Empty(t, chainConfigList, "an invalid row is not stored")
+
+	_, err = as.UpsertTokenConfig(ctx, &gasfee.UpsertTokenConfigRequest{Token: &gasfee.TokenConfig{
+		Symbol: "bnb", Decimal: 18, Enabled: true,
+	}})
+	require.NoError(t, err)
+	_, err = as.UpsertChainConfig(ctx, &gasfee.UpsertChainConfigRequest{Chain: &gasfee.ChainConfig{
+		ChainId: 56, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18, Enabled: true,
+	}})
+	require.NoError(t, err)
+}
+
+func TestUpsertTokenConfigValidatesMergedConfig(t *testing.T) {
+	as, db := newTestAdmin(t)
+
+	// the yaml chain prices its gas in eth
+	_, err := as.UpsertTokenConfig(context.Background(), &gasfee.UpsertTokenConfigRequest{Token: &gasfee.TokenConfig{
+		Symbol: "eth", Enabled: false,
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	tokenConfigList, err := db.TokenConfig.QueryTokenConfigList()
+	require.NoError(t, err)
+	require.Empty(t, tokenConfigList)
+}
+
+func TestUpsertTokenConfigRejectsCollisions(t *testing.T) {
+	as, db := newTestAdmin(t)
+	as.cfg.Symbols[0].Addresses = []config.TokenAddress{{ChainId: 1, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}}
+	ctx := context.Background()
+
+	_, err := as.UpsertTokenConfig(ctx, &gasfee.UpsertTokenConfigRequest{Token: &gasfee.TokenConfig{
+		Symbol: "pol", Decimal: 18, Enabled: true, Aliases: []string{"matic"},
+	}})
+	require.NoError(t, err)
+
+	for name, token := range map[string]*gasfee.TokenConfig{
+		"alias of a yaml symbol":       {Symbol: "weth", Decimal: 18, Enabled: true, Aliases: []string{"ETH"}},
+		"alias of a registry symbol":   {Symbol: "wpol", Decimal: 18, Enabled: true, Aliases: []string{"Matic"}},
+		"name of another symbol alias": {Symbol: "MATIC", Decimal: 18, Enabled: true},
+		"address of a yaml symbol": {Symbol: "weth", Decimal: 18, Enabled: true, Addresses: []*gasfee.TokenAddress{
+			{ChainId: 1, Address: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"},
+		}},
+		"two addresses on one chain": {Symbol: "weth", Decimal: 18, Enabled: true, Addresses: []*gasfee.TokenAddress{
+			{ChainId: 10, Address: "0x4200000000000000000000000000000000000006"},
+			{ChainId: 10, Address: "0x4200000000000000000000000000000000000007"},
+		}},
+	} {
+		_, err := as.UpsertTokenConfig(ctx, &gasfee.UpsertTokenConfigRequest{Token: token})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+	tokenConfigList, err := db.TokenConfig.QueryTokenConfigList()
+	require.NoError(t, err)
+	require.Len(t, tokenConfigList, 1)
+
+	// a symbol may keep its own aliases when it is updated
+	_, err = as.UpsertTokenConfig(ctx, &gasfee.UpsertTokenConfigRequest{Token: &gasfee.TokenConfig{
+		Symbol: "pol", Decimal: 18, Enabled: true, Aliases: []string{"MATIC", "wpol"},
+	}})
+	require.NoError(t, err)
+}
+
+func TestDeleteRejectsInvalidRegistry(t *testing.T) {
+	as, db := newTestAdmin(t)
+	ctx := context.Background()
+
+	_, err := as.UpsertTokenConfig(ctx, &gasfee.UpsertTokenConfigRequest{Token: &gasfee.TokenConfig{
+		Symbol: "bnb", Decimal: 18, Enabled: true,
+	}})
+	require.NoError(t, err)
+	_, err = as.UpsertChainConfig(ctx, &gasfee.UpsertChainConfigRequest{Chain: &gasfee.ChainConfig{
+		ChainId: 56, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18, Enabled: true,
+	}})
+	require.NoError(t, err)
+
+	// bsc still prices its gas in bnb
+	_, err = as.DeleteTokenConfig(ctx, &gasfee.DeleteTokenConfigRequest{Symbol: "bnb"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.ErrorContains(t, err, `native_token BNB has no matching entry "bnb" in symbols`)
+	tokenConfigList, err := db.TokenConfig.QueryTokenConfigList()
+	require.NoError(t, err)
+	require.Len(t, tokenConfigList, 1, "the referenced token is kept")
+
+	_, err = as.DeleteChainConfig(ctx, &gasfee.DeleteChainConfigRequest{ChainId: 56})
+	require.NoError(t, err)
+	_, err = as.DeleteTokenConfig(ctx, &gasfee.DeleteTokenConfigRequest{Symbol: "bnb"})
+	require.NoError(t, err)
+	_, err = as.DeleteTokenConfig(ctx, &gasfee.DeleteTokenConfigRequest{Symbol: "bnb"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
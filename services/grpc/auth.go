@@ -0,0 +1,283 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// ConsumerTokenMetadataKey carries the consumer token for requests that do not
+// set it in the message body, such as streams.
+const ConsumerTokenMetadataKey = "consumer-token"
+
+type Consumer struct {
+	Name  string
+	Token string
+}
+
+type consumerContextKey struct{}
+
+// ConsumerFromContext returns the consumer authenticated for the current request.
+func ConsumerFromContext(ctx context.Context) (*Consumer, bool) {
+	consumer, ok := ctx.Value(consumerContextKey{}).(*Consumer)
+	return consumer, ok
+}
+
+type consumerTokenRequest interface {
+	GetConsumerToken() string
+}
+
+const (
+	// invalidTokenTTL is how long a token unknown to the database is rejected
+	// without asking it again
+	invalidTokenTTL = time.Minute
+	// maxInvalidTokens bounds the remembered unknown tokens, expired ones are
+	// swept when it is reached
+	maxInvalidTokens = 10000
+	// auditBufferSize is how many audits may wait for the writer before new
+	// ones are dropped
+	auditBufferSize = 1024
+)
+
+type ConsumerAuth struct {
+	enable    bool
+	consumers []config.Consumer
+	db        *database.DB
+
+	// invalid holds when each unknown token was last looked up
+	mu      sync.Mutex
+	invalid map[string]time.Time
+
+	// audits are stored by a writer goroutine until Close
+	auditMu   sync.RWMutex
+	closed    bool
+	audits    chan *database.ApiConsumerAudit
+	auditDone chan struct{}
+}
+
+func NewConsumerAuth(conf config.Auth, db *database.DB) *ConsumerAuth {
+	ca := &ConsumerAuth{
+		enable:    conf.Enable,
+		consumers: conf.Consumers,
+		db:        db,
+		invalid:   make(map[string]time.Time),
+		audits:    make(chan *database.ApiConsumerAudit, auditBufferSize),
+		auditDone: make(chan struct{}),
+	}
+	go ca.writeAudits()
+	return ca
+}
+
+// Close stores the audits still queued and stops the audit writer.
+func (ca *ConsumerAuth) Close() {
+	ca.auditMu.Lock()
+	if !ca.closed {
+		ca.closed = true
+		close(ca.audits)
+	}
+	ca.auditMu.Unlock()
+	<-ca.auditDone
+}
+
+// writeAudits stores audits off the request path.
+func (ca *ConsumerAuth) writeAudits() {
+	defer close(ca.auditDone)
+	for audit := range ca.audits {
+		if err := ca.db.ApiConsumer.StoreApiConsumerAudit(audit); err != nil {
+			log.Error("store api consumer audit fail", "consumer", audit.ConsumerName, "err", err)
+		}
+	}
+}
+
+// reflectionMethodPrefixes are the server reflection services, which only
+// describe the api and stay open to tools like grpcurl.
+var reflectionMethodPrefixes = []string{
+	"/" + grpc_reflection_v1.ServerReflection_ServiceDesc.ServiceName + "/",
+	"/" + grpc_reflection_v1alpha.ServerReflection_ServiceDesc.ServiceName + "/",
+}
+
+// skipsConsumerAuth reports whether method is served without a consumer
+// token: admin methods are authenticated by the admin token instead.
+func skipsConsumerAuth(method string) bool {
+	if isAdminMethod(method) {
+		return true
+	}
+	for _, prefix := range reflectionMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ca *ConsumerAuth) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !ca.enable || skipsConsumerAuth(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	var token string
+	if tokenReq, ok := req.(consumerTokenRequest); ok {
+		token = tokenReq.GetConsumerToken()
+	}
+	if token == "" {
+		token = tokenFromMetadata(ctx)
+	}
+	consumer, err := ca.authenticate(ctx, token, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, consumerContextKey{}, consumer), req)
+}
+
+func (ca *ConsumerAuth) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !ca.enable || skipsConsumerAuth(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	consumer, err := ca.authenticate(ss.Context(), tokenFromMetadata(ss.Context()), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &wrappedServerStream{
+		ServerStream: ss,
+		ctx:          context.WithValue(ss.Context(), consumerContextKey{}, consumer),
+	})
+}
+
+func (ca *ConsumerAuth) authenticate(ctx context.Context, token string, method string) (*Consumer, error) {
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing consumer token")
+	}
+
+	now := time.Now()
+	var name string
+	if consumer, ok := ca.configConsumer(token); ok {
+		if consumer.Disabled {
+			return nil, status.Error(codes.PermissionDenied, "consumer disabled")
+		}
+		if !consumer.ExpireAt.IsZero() && now.After(consumer.ExpireAt) {
+			return nil, status.Error(codes.PermissionDenied, "consumer token expired")
+		}
+		name = consumer.Name
+	} else {
+		if ca.knownInvalid(token, now) {
+			return nil, status.Error(codes.Unauthenticated, "invalid consumer token")
+		}
+		apiConsumer, err := ca.db.ApiConsumer.QueryApiConsumer(token)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "query api consumer fail")
+		}
+		if apiConsumer == nil {
+			ca.rememberInvalid(token, now)
+			return nil, status.Error(codes.Unauthenticated, "invalid consumer token")
+		}
+		if !apiConsumer.Enabled {
+			return nil, status.Error(codes.PermissionDenied, "consumer disabled")
+		}
+		if apiConsumer.ExpireAt != 0 && uint64(now.Unix()) > apiConsumer.ExpireAt {
+			return nil, status.Error(codes.PermissionDenied, "consumer token expired")
+		}
+		name = apiConsumer.Name
+	}
+
+	var peerAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		peerAddr = p.Addr.String()
+	}
+	log.Info("api consumer request", "consumer", name, "method", method, "peer", peerAddr)
+	ca.audit(&database.ApiConsumerAudit{
+		ConsumerName: name,
+		Method:       method,
+		PeerAddr:     peerAddr,
+		Timestamp:    uint64(now.Unix()),
+	})
+	return &Consumer{Name: name, Token: token}, nil
+}
+
+// configConsumer finds the yaml consumer of token, comparing every token in
+// constant time.
+func (ca *ConsumerAuth) configConsumer(token string) (config.Consumer, bool) {
+	var found config.Consumer
+	var ok bool
+	for _, consumer := range ca.consumers {
+		if subtle.ConstantTimeCompare([]byte(consumer.Token), []byte(token)) == 1 && !ok {
+			found, ok = consumer, true
+		}
+	}
+	return found, ok
+}
+
+func (ca *ConsumerAuth) knownInvalid(token string, now time.Time) bool {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	seen, ok := ca.invalid[token]
+	if ok && now.Sub(seen) >= invalidTokenTTL {
+		delete(ca.invalid, token)
+		return false
+	}
+	return ok
+}
+
+func (ca *ConsumerAuth) rememberInvalid(token string, now time.Time) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if len(ca.invalid) >= maxInvalidTokens {
+		for cached, seen := range ca.invalid {
+			if now.Sub(seen) >= invalidTokenTTL {
+				delete(ca.invalid, cached)
+			}
+		}
+		// still full of live entries, start over rather than grow
+		if len(ca.invalid) >= maxInvalidTokens {
+			clear(ca.invalid)
+		}
+	}
+	ca.invalid[token] = now
+}
+
+// audit queues the audit for the writer, dropping it when the writer falls behind.
+func (ca *ConsumerAuth) audit(audit *database.ApiConsumerAudit) {
+	ca.auditMu.RLock()
+	defer ca.auditMu.RUnlock()
+	if ca.closed {
+		return
+	}
+	select {
+	case ca.audits <- audit:
+	default:
+		log.Warn("api consumer audit queue full, dropping audit", "consumer", audit.ConsumerName, "method", audit.Method)
+	}
+}
+
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(ConsumerTokenMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+type wrappedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedServerStream) Context() context.Context {
+	return s.ctx
+}
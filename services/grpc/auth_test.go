@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/proto/gasfee"
+)
+
+// fakeConsumers counts the consumer lookups and keeps the audits stored.
+type fakeConsumers struct {
+	mu        sync.Mutex
+	consumers map[string]*database.ApiConsumer
+	queries   int
+	audits    []database.ApiConsumerAudit
+}
+
+func (f *fakeConsumers) QueryApiConsumer(token string) (*database.ApiConsumer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+	return f.consumers[token], nil
+}
+
+func (f *fakeConsumers) StoreApiConsumerAudit(audit *database.ApiConsumerAudit) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.audits = append(f.audits, *audit)
+	return nil
+}
+
+func newTestAuth(consumers *fakeConsumers) *ConsumerAuth {
+	db := database.NewMemoryDB()
+	db.ApiConsumer = consumers
+	return NewConsumerAuth(config.Auth{
+		Enable:    true,
+		Consumers: []config.Consumer{{Name: "yaml", Token: "yaml-token"}},
+	}, db)
+}
+
+func TestAuthenticateCachesUnknownTokens(t *testing.T) {
+	consumers := &fakeConsumers{consumers: map[string]*database.ApiConsumer{
+		"db-token": {Name: "db", Token: "db-token", Enabled: true},
+	}}
+	ca := newTestAuth(consumers)
+	defer ca.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := ca.authenticate(context.Background(), "garbage", "/m")
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	require.Equal(t, 1, consumers.queries, "an unknown token is looked up once")
+
+	consumer, err := ca.authenticate(context.Background(), "db-token", "/m")
+	require.NoError(t, err)
+	require.Equal(t, "db", consumer.Name)
+
+	consumer, err = ca.authenticate(context.Background(), "yaml-token", "/m")
+	require.NoError(t, err)
+	require.Equal(t, "yaml", consumer.Name)
+	require.Equal(t, 2, consumers.queries, "yaml tokens never reach the database")
+}
+
+func TestAuthenticateWritesAuditsAsync(t *testing.T) {
+	consumers := &fakeConsumers{}
+	ca := newTestAuth(consumers)
+
+	for i := 0; i < 5; i++ {
+		_, err := ca.authenticate(context.Background(), "yaml-token", "/m")
+		require.NoError(t, err)
+	}
+	// Close waits for the queued audits
+	ca.Close()
+	require.Len(t, consumers.audits, 5)
+	require.Equal(t, "yaml", consumers.audits[0].ConsumerName)
+
+	_, err := ca.authenticate(context.Background(), "yaml-token", "/m")
+	require.NoError(t, err, "requests finishing after close still authenticate")
+	require.Len(t, consumers.audits, 5)
+}
+
+func TestKnownInvalidExpires(t *testing.T) {
+	ca := newTestAuth(&fakeConsumers{})
+	defer ca.Close()
+	now := time.Now()
+	ca.rememberInvalid("garbage", now)
+	require.True(t, ca.knownInvalid("garbage", now.Add(invalidTokenTTL-1)))
+	require.False(t, ca.knownInvalid("garbage", now.Add(invalidTokenTTL)))
+}
+
+func TestReflectionNeedsNoConsumerToken(t *testing.T) {
+	port := freePort(t)
+	ms, err := NewTokenPriceRpcService(&TokenPriceRpcConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		Auth: config.Auth{Enable: true, Consumers: []config.Consumer{{Name: "yaml", Token: "yaml-token"}}},
+	}, database.NewMemoryDB())
+	require.NoError(t, err)
+	require.NoError(t, ms.Start(context.Background()))
+	defer ms.Stop(context.Background())
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	require.Contains(t, services, gasfee.TokenGasPriceServices_ServiceDesc.ServiceName)
+
+	_, err = gasfee.NewTokenGasPriceServicesClient(conn).ListSupportedChains(ctx, &gasfee.ListSupportedChainsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), "the api still needs a token")
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	ethevent "github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/event"
+)
+
+const eventBufferSize = 64
+
+// DefaultCacheTTL bounds the age of cached lookups when the config sets none.
+const DefaultCacheTTL = 10 * time.Second
+
+// latestCache holds the most recent gas fee per chain and price per symbol and quote currency
+// delivered in-process, so lookups skip the database when the indexer runs
+// alongside the server. Entries older than ttl are ignored: once this replica
+// loses its lease nothing is delivered any more, and the leader's rows in the
+// database are newer.
+type latestCache struct {
+	mu          sync.RWMutex
+	ttl         time.Duration
+	gasFees     map[string]cacheEntry[*database.GasFee]
+	tokenPrices map[string]cacheEntry[*database.TokenPrice]
+}
+
+type cacheEntry[T any] struct {
+	value T
+	at    time.Time
+}
+
+func newLatestCache(ttl time.Duration) *latestCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &latestCache{
+		ttl:         ttl,
+		gasFees:     make(map[string]cacheEntry[*database.GasFee]),
+		tokenPrices: make(map[string]cacheEntry[*database.TokenPrice]),
+	}
+}
+
+func (c *latestCache) storeGasFee(gasFee *database.GasFee, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gasFees[gasFee.ChainId.String()] = cacheEntry[*database.GasFee]{value: gasFee, at: now}
+}
+
+func (c *latestCache) storeTokenPrice(tokenPrice *database.TokenPrice, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokenPrices[tokenPriceKey(tokenPrice.TokenSymbol, tokenPrice.QuoteCurrency)] = cacheEntry[*database.TokenPrice]{value: tokenPrice, at: now}
+}
+
+func (c *latestCache) gasFee(chainId string, now time.Time) (*database.GasFee, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.gasFees[chainId]
+	if !ok || now.Sub(entry.at) >= c.ttl {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *latestCache) tokenPrice(symbol string, quoteCurrency string, now time.Time) (*database.TokenPrice, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.tokenPrices[tokenPriceKey(symbol, quoteCurrency)]
+	if !ok || now.Sub(entry.at) >= c.ttl {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// SubscribeEvents feeds the lookup cache from bus until the service stops.
+func (ms *TokenPriceRpcService) SubscribeEvents(bus *event.Bus) {
+	gasFeeCh := make(chan event.GasFeeEvent, eventBufferSize)
+	tokenPriceCh := make(chan event.TokenPriceEvent, eventBufferSize)
+	gasFeeSub := bus.SubscribeGasFee(gasFeeCh)
+	tokenPriceSub := bus.SubscribeTokenPrice(tokenPriceCh)
+	ms.cache = newLatestCache(ms.CacheTTL)
+	ms.eventSubs = append(ms.eventSubs, gasFeeSub, tokenPriceSub)
+
+	go func() {
+		for {
+			select {
+			case ev := <-gasFeeCh:
+				ms.cache.storeGasFee(ev.GasFee, time.Now())
+			case ev := <-tokenPriceCh:
+				ms.cache.storeTokenPrice(ev.TokenPrice, time.Now())
+			case err := <-gasFeeSub.Err():
+				logSubscriptionEnd(err)
+				return
+			case err := <-tokenPriceSub.Err():
+				logSubscriptionEnd(err)
+				return
+			}
+		}
+	}()
+}
+
+func logSubscriptionEnd(err error) {
+	if err != nil {
+		log.Error("event subscription fail", "err", err)
+	}
+}
+
+func (ms *TokenPriceRpcService) unsubscribeEvents() {
+	for _, sub := range ms.eventSubs {
+		sub.Unsubscribe()
+	}
+	ms.eventSubs = []ethevent.Subscription{}
+}
+
+func (ms *TokenPriceRpcService) queryGasFee(chainId string) (*database.GasFee, error) {
+	if ms.cache != nil {
+		if gasFee, ok := ms.cache.gasFee(chainId, time.Now()); ok {
+			return gasFee, nil
+		}
+	}
+	return ms.db.GasFee.QueryGasFees(chainId)
+}
+
+func (ms *TokenPriceRpcService) queryTokenPrice(symbol string, quoteCurrency string) (*database.TokenPrice, error) {
+	if ms.cache != nil {
+		if tokenPrice, ok := ms.cache.tokenPrice(symbol, quoteCurrency, time.Now()); ok {
+			return tokenPrice, nil
+		}
+	}
+	return ms.db.TokenPrice.QueryTokenPrices(symbol, quoteCurrency)
+}
+
+func tokenPriceKey(symbol string, quoteCurrency string) string {
+	return symbol + "/" + quoteCurrency
+}
@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/event"
+)
+
+func TestLatestCacheExpires(t *testing.T) {
+	cache := newLatestCache(time.Minute)
+	now := time.Now()
+	cache.storeGasFee(&database.GasFee{ChainId: big.NewInt(1), GasPrice: big.NewInt(7)}, now)
+	cache.storeTokenPrice(&database.TokenPrice{TokenSymbol: "eth", QuoteCurrency: "USD", MarketPrice: big.NewRat(1, 1)}, now)
+
+	gasFee, ok := cache.gasFee("1", now.Add(time.Minute-1))
+	require.True(t, ok)
+	require.Equal(t, int64(7), gasFee.GasPrice.Int64())
+	_, ok = cache.gasFee("1", now.Add(time.Minute))
+	require.False(t, ok)
+
+	_, ok = cache.tokenPrice("eth", "USD", now.Add(time.Minute-1))
+	require.True(t, ok)
+	_, ok = cache.tokenPrice("eth", "EUR", now)
+	require.False(t, ok)
+	_, ok = cache.tokenPrice("eth", "USD", now.Add(time.Minute))
+	require.False(t, ok)
+
+	require.Equal(t, DefaultCacheTTL, newLatestCache(0).ttl)
+}
+
+func TestQueryFallsBackToDatabaseOnStaleCache(t *testing.T) {
+	db := database.NewMemoryDB()
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&database.GasFee{ChainId: big.NewInt(1), GasPrice: big.NewInt(9)}))
+	ms := &TokenPriceRpcService{TokenPriceRpcConfig: &TokenPriceRpcConfig{CacheTTL: time.Minute}, db: db}
+	bus := event.NewBus()
+	ms.SubscribeEvents(bus)
+	defer ms.unsubscribeEvents()
+
+	bus.PublishGasFee(&database.GasFee{ChainId: big.NewInt(1), GasPrice: big.NewInt(7)})
+	require.Eventually(t, func() bool {
+		gasFee, err := ms.queryGasFee("1")
+		return err == nil && gasFee.GasPrice.Int64() == 7
+	}, time.Second, time.Millisecond, "a published fee is served from the cache")
+
+	ms.cache.storeGasFee(&database.GasFee{ChainId: big.NewInt(1), GasPrice: big.NewInt(7)}, time.Now().Add(-time.Minute))
+	gasFee, err := ms.queryGasFee("1")
+	require.NoError(t, err)
+	require.Equal(t, int64(9), gasFee.GasPrice.Int64(), "a stale entry falls through to the database")
+}
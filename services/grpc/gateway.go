@@ -0,0 +1,307 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/cpchain-network/gas-oracle/proto/gasfee"
+)
+
+// ConsumerTokenHeader carries the consumer token for REST callers; the
+// consumer_token query parameter is accepted as well.
+const ConsumerTokenHeader = "X-Consumer-Token"
+
+const gatewayReadHeaderTimeout = 10 * time.Second
+
+var gatewayMarshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+type tokenPriceRequest struct {
+	ConsumerToken string
+	// Symbol may be a token address on ChainId
+	Symbol        string
+	ChainId       uint64
+	QuoteCurrency string
+}
+
+func (r *tokenPriceRequest) GetConsumerToken() string {
+	return r.ConsumerToken
+}
+
+type chainListRequest struct {
+	ConsumerToken string
+}
+
+func (r *chainListRequest) GetConsumerToken() string {
+	return r.ConsumerToken
+}
+
+type TokenPriceResponse struct {
+	Symbol         string  `json:"symbol"`
+	TokenName      string  `json:"token_name"`
+	Decimal        uint8   `json:"decimal"`
+	MarketPrice    string  `json:"market_price"`
+	QuoteCurrency  string  `json:"quote_currency"`
+	DerivationPath string  `json:"derivation_path"`
+	Confidence     float64 `json:"confidence"`
+	// PriceState is frozen while the circuit breaker keeps the last good price
+	PriceState       string `json:"price_state"`
+	PriceStateReason string `json:"price_state_reason"`
+	Timestamp        uint64 `json:"timestamp"`
+}
+
+type ChainResponse struct {
+	ChainId    string `json:"chain_id"`
+	TokenName  string `json:"token_name"`
+	Decimal    uint8  `json:"decimal"`
+	PredictFee string `json:"predict_fee"`
+	Timestamp  uint64 `json:"timestamp"`
+}
+
+type ChainListResponse struct {
+	Chains []ChainResponse `json:"chains"`
+}
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (ms *TokenPriceRpcService) startGateway() error {
+	httpAddr := fmt.Sprintf("%s:%d", ms.TokenPriceRpcConfig.Host, ms.TokenPriceRpcConfig.HttpPort)
+	listener, err := net.Listen("tcp", httpAddr)
+	if err != nil {
+		return fmt.Errorf("could not start http gateway listener: %w", err)
+	}
+
+	ms.gateway = &http.Server{
+		Handler:           ms.gatewayHandler(),
+		ReadHeaderTimeout: gatewayReadHeaderTimeout,
+	}
+	go func() {
+		log.Info("http gateway info", "addr", listener.Addr())
+		if err := ms.gateway.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("start http gateway fail", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (ms *TokenPriceRpcService) gatewayHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/chains/{chainId}/gas", ms.handleChainGas)
+	mux.HandleFunc("GET /v1/prices/{symbol}", ms.handleTokenPrice)
+	mux.HandleFunc("GET /v1/chains", ms.handleChainList)
+	return mux
+}
+
+func (ms *TokenPriceRpcService) handleChainGas(w http.ResponseWriter, r *http.Request) {
+	chainId, err := strconv.ParseUint(r.PathValue("chainId"), 10, 64)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, "invalid chain id"))
+		return
+	}
+	symbol := r.URL.Query().Get("symbol")
+	if symbol == "" {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, "missing symbol"))
+		return
+	}
+
+	req := &gasfee.TokenGasPriceRequest{
+		ConsumerToken: consumerTokenFromRequest(r),
+		ChainId:       chainId,
+		Symbol:        symbol,
+		QuoteCurrency: r.URL.Query().Get("quote"),
+	}
+	ms.invokeGateway(w, r, gasfee.TokenGasPriceServices_GetTokenPriceAndGasByChainId_FullMethodName, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ms.GetTokenPriceAndGasByChainId(ctx, req.(*gasfee.TokenGasPriceRequest))
+	})
+}
+
+func (ms *TokenPriceRpcService) handleTokenPrice(w http.ResponseWriter, r *http.Request) {
+	req := &tokenPriceRequest{
+		ConsumerToken: consumerTokenFromRequest(r),
+		Symbol:        r.PathValue("symbol"),
+		QuoteCurrency: r.URL.Query().Get("quote"),
+	}
+	if chainId := r.URL.Query().Get("chain_id"); chainId != "" {
+		var err error
+		if req.ChainId, err = strconv.ParseUint(chainId, 10, 64); err != nil {
+			writeGatewayError(w, status.Error(codes.InvalidArgument, "invalid chain id"))
+			return
+		}
+	}
+	ms.invokeGateway(w, r, "/v1/prices", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		quoteCurrency, err := ms.quoteCurrency(req.(*tokenPriceRequest).QuoteCurrency)
+		if err != nil {
+			return nil, err
+		}
+		symbol, err := ms.resolveSymbol(req.(*tokenPriceRequest).ChainId, req.(*tokenPriceRequest).Symbol)
+		if err != nil {
+			return nil, err
+		}
+		tokenPrice, err := ms.queryTokenPrice(symbol, quoteCurrency)
+		if err != nil {
+			return nil, queryError(err, "token price")
+		}
+		return &TokenPriceResponse{
+			Symbol:           tokenPrice.TokenSymbol,
+			TokenName:        tokenPrice.TokenName,
+			Decimal:          tokenPrice.Decimal,
+			MarketPrice:      tokenPrice.MarketPriceString(),
+			QuoteCurrency:    tokenPrice.QuoteCurrency,
+			DerivationPath:   tokenPrice.DerivationPath,
+			Confidence:       tokenPrice.Confidence,
+			PriceState:       tokenPrice.PriceState,
+			PriceStateReason: tokenPrice.PriceStateReason,
+			Timestamp:        tokenPrice.Timestamp,
+		}, nil
+	})
+}
+
+func (ms *TokenPriceRpcService) handleChainList(w http.ResponseWriter, r *http.Request) {
+	req := &chainListRequest{ConsumerToken: consumerTokenFromRequest(r)}
+	ms.invokeGateway(w, r, "/v1/chains", req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		gasFeeList, err := ms.db.GasFee.QueryGasFeeList()
+		if err != nil {
+			return nil, status.Error(codes.Internal, "query chain list fail")
+		}
+		chains := make([]ChainResponse, 0, len(gasFeeList))
+		for _, gasFee := range gasFeeList {
+			chains = append(chains, ChainResponse{
+				ChainId:    gasFee.ChainId.String(),
+				TokenName:  gasFee.TokenName,
+				Decimal:    gasFee.Decimal,
+				PredictFee: gasFee.PredictFee.String(),
+				Timestamp:  gasFee.Timestamp,
+			})
+		}
+		return &ChainListResponse{Chains: chains}, nil
+	})
+}
+
+// invokeGateway runs handler behind the same interceptors as the gRPC server,
+// so REST callers are authenticated, rate limited and audited alike.
+func (ms *TokenPriceRpcService) invokeGateway(w http.ResponseWriter, r *http.Request, method string, req interface{}, handler grpc.UnaryHandler) {
+	stream := &gatewayTransportStream{method: method, header: metadata.MD{}}
+	ctx := grpc.NewContextWithServerTransportStream(r.Context(), stream)
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ConsumerTokenMetadataKey, consumerTokenFromRequest(r)))
+
+	info := &grpc.UnaryServerInfo{Server: ms, FullMethod: method}
+	resp, err := chainUnaryInterceptors(ms.unaryInterceptors)(ctx, req, info, handler)
+
+	if retryAfter := stream.header.Get(RetryAfterMetadataKey); len(retryAfter) > 0 {
+		w.Header().Set("Retry-After", retryAfter[0])
+	}
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	writeGatewayJSON(w, http.StatusOK, resp)
+}
+
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func consumerTokenFromRequest(r *http.Request) string {
+	if token := r.Header.Get(ConsumerTokenHeader); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("consumer_token")
+}
+
+func writeGatewayJSON(w http.ResponseWriter, code int, resp interface{}) {
+	var body []byte
+	var err error
+	if msg, ok := resp.(*gasfee.TokenGasPriceResponse); ok {
+		body, err = gatewayMarshaler.Marshal(msg)
+	} else {
+		body, err = json.Marshal(resp)
+	}
+	if err != nil {
+		log.Error("marshal gateway response fail", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		log.Warn("write gateway response fail", "err", err)
+	}
+}
+
+func writeGatewayError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeGatewayJSON(w, httpStatusFromCode(st.Code()), &errorResponse{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	})
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// gatewayTransportStream lets interceptors set response headers the same way
+// they do on a real gRPC stream.
+type gatewayTransportStream struct {
+	method string
+	header metadata.MD
+}
+
+func (s *gatewayTransportStream) Method() string {
+	return s.method
+}
+
+func (s *gatewayTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *gatewayTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *gatewayTransportStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+func newTestGateway(t *testing.T) (http.Handler, *database.DB) {
+	db := database.NewMemoryDB()
+	registry := &config.Config{
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		SkyeyeUrl:    "http://skyeye",
+		Symbols:      []config.Symbols{{Name: "eth", Decimal: 18}, {Name: "usdt", Decimal: 6}},
+		RPCs:         []*config.RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH", Decimal: 18}},
+	}
+	require.NoError(t, registry.Validate())
+	ms, err := NewTokenPriceRpcService(&TokenPriceRpcConfig{Registry: registry}, db)
+	require.NoError(t, err)
+	t.Cleanup(ms.consumerAuth.Close)
+	return ms.gatewayHandler(), db
+}
+
+func getGateway(t *testing.T, handler http.Handler, target string) (int, errorResponse) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	return recorder.Code, resp
+}
+
+func TestGatewayChainGasNotFound(t *testing.T) {
+	handler, db := newTestGateway(t)
+
+	code, resp := getGateway(t, handler, "/v1/chains/56/gas?symbol=usdt")
+	require.Equal(t, http.StatusNotFound, code, "an unknown chain")
+	require.Equal(t, "gas fee not found", resp.Message)
+
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&database.GasFee{ChainId: big.NewInt(1), TokenName: "ETH", Decimal: 18, PredictFee: big.NewInt(1)}))
+	code, resp = getGateway(t, handler, "/v1/chains/1/gas?symbol=usdt")
+	require.Equal(t, http.StatusNotFound, code, "a missing native price")
+	require.Equal(t, "native token price not found", resp.Message)
+
+	require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&database.TokenPrice{TokenName: "eth", TokenSymbol: "eth", QuoteCurrency: "USD", Decimal: 18, MarketPrice: big.NewRat(3000, 1)}))
+	code, resp = getGateway(t, handler, "/v1/chains/1/gas?symbol=usdt")
+	require.Equal(t, http.StatusNotFound, code, "a missing token price")
+	require.Equal(t, "token price not found", resp.Message)
+
+	require.NoError(t, db.TokenPrice.StoreOrUpdateTokenPrice(&database.TokenPrice{TokenName: "usdt", TokenSymbol: "usdt", QuoteCurrency: "USD", Decimal: 6, MarketPrice: big.NewRat(1, 1)}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/chains/1/gas?symbol=usdt", nil))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+}
@@ -2,61 +2,215 @@ package grpc
 
 import (
 	"context"
-	"fmt"
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 
+	"github.com/cpchain-network/gas-oracle/common/symbols"
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
 	"github.com/cpchain-network/gas-oracle/proto/gasfee"
 )
 
 func (ms *TokenPriceRpcService) GetTokenPriceAndGasByChainId(ctx context.Context, in *gasfee.TokenGasPriceRequest) (*gasfee.TokenGasPriceResponse, error) {
-	gasFee, err := ms.db.GasFee.QueryGasFees(strconv.FormatUint(in.ChainId, 10))
+	quoteCurrency, err := ms.quoteCurrency(in.QuoteCurrency)
 	if err != nil {
-		log.Error("Query gas fee fail", "err", err)
 		return nil, err
 	}
 
-	nativeTokenPrice, err := ms.db.TokenPrice.QueryTokenPrices(strings.ToLower(gasFee.TokenName))
+	symbol, err := ms.resolveSymbol(in.ChainId, in.Symbol)
 	if err != nil {
-		log.Error("Query native token price fail", "err", err)
 		return nil, err
 	}
 
-	tokenPrice, err := ms.db.TokenPrice.QueryTokenPrices(in.Symbol)
+	gasFee, err := ms.queryGasFee(strconv.FormatUint(in.ChainId, 10))
 	if err != nil {
-		log.Error("Query token price fail", "err", err)
-		return nil, err
+		return nil, queryError(err, "gas fee")
 	}
 
-	log.Info("get gas fee success", "predictFee", gasFee.PredictFee, "tokenName", gasFee.TokenName, "decimal", gasFee.Decimal)
-	log.Info("get token price success", "marketPrice", tokenPrice.MarketPrice)
+	nativeSymbol, ok := ms.symbolMap().Resolve(gasFee.TokenName)
+	if !ok {
+		// the native token was removed from the symbols, try its lower-cased name
+		nativeSymbol = strings.ToLower(gasFee.TokenName)
+	}
+	nativeTokenPrice, err := ms.queryTokenPrice(nativeSymbol, quoteCurrency)
+	if err != nil {
+		return nil, queryError(err, "native token price")
+	}
 
-	fGasFee, err := strconv.ParseFloat(gasFee.PredictFee, 64)
+	tokenPrice, err := ms.queryTokenPrice(symbol, quoteCurrency)
 	if err != nil {
-		log.Error("fee convert fail", "err", err)
-		return nil, errors.New("fee convert fail")
+		return nil, queryError(err, "token price")
 	}
 
-	log.Info("parse float success", "fGasFee", fGasFee)
+	log.Info("get gas fee success", "predictFee", gasFee.PredictFee, "tokenName", gasFee.TokenName, "decimal", gasFee.Decimal)
+	log.Info("get token price success", "marketPrice", tokenPrice.MarketPriceString(), "quoteCurrency", quoteCurrency)
+
+	if gasFee.PredictFee == nil || nativeTokenPrice.MarketPrice == nil || tokenPrice.MarketPrice == nil || tokenPrice.MarketPrice.Sign() == 0 {
+		log.Error("fee convert fail", "chainId", in.ChainId, "symbol", in.Symbol)
+		return nil, status.Error(codes.Internal, "fee convert fail")
+	}
 
+	// the fee in native token units, converted to the symbol at market prices
 	resultValue := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(gasFee.Decimal)), nil)
-	value, _ := resultValue.Float64()
+	pFee := new(big.Rat).SetFrac(gasFee.PredictFee, resultValue)
+	pFee.Mul(pFee, nativeTokenPrice.MarketPrice)
+	pFee.Quo(pFee, tokenPrice.MarketPrice)
+
+	priceState, priceStateReason := combinedPriceState(tokenPrice, nativeTokenPrice)
+	return &gasfee.TokenGasPriceResponse{
+		ReturnCode:       100,
+		Message:          "get gas fee success",
+		PredictFee:       pFee.FloatString(8),
+		Symbol:           in.Symbol,
+		MarketPrice:      tokenPrice.MarketPriceString(),
+		QuoteCurrency:    quoteCurrency,
+		PriceState:       priceState,
+		PriceStateReason: priceStateReason,
+	}, nil
+}
 
-	nativeTokenMarketPrice, _ := strconv.ParseFloat(nativeTokenPrice.MarketPrice, 64)
-	symbolMarketPrice, _ := strconv.ParseFloat(tokenPrice.MarketPrice, 64)
+// queryError is NotFound for a missing row of what and Internal, logged, for
+// any other failed query.
+func queryError(err error, what string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Errorf(codes.NotFound, "%s not found", what)
+	}
+	log.Error("Query "+what+" fail", "err", err)
+	return status.Errorf(codes.Internal, "query %s fail", what)
+}
 
-	pFee := (fGasFee / value) * (nativeTokenMarketPrice / symbolMarketPrice)
+// combinedPriceState is frozen when any of the prices a fee is computed from
+// is, with the reason of each frozen one.
+func combinedPriceState(tokenPrices ...*database.TokenPrice) (string, string) {
+	var reasons []string
+	for _, tokenPrice := range tokenPrices {
+		if tokenPrice.PriceState == database.PriceStateFrozen {
+			reasons = append(reasons, tokenPrice.TokenSymbol+"/"+tokenPrice.QuoteCurrency+": "+tokenPrice.PriceStateReason)
+		}
+	}
+	if len(reasons) == 0 {
+		return database.PriceStateOk, ""
+	}
+	return database.PriceStateFrozen, strings.Join(reasons, "; ")
+}
 
-	return &gasfee.TokenGasPriceResponse{
-		ReturnCode:  100,
-		Message:     "get gas fee success",
-		PredictFee:  fmt.Sprintf("%.8f", pFee),
-		Symbol:      in.Symbol,
-		MarketPrice: tokenPrice.MarketPrice,
+func (ms *TokenPriceRpcService) ListSupportedChains(ctx context.Context, in *gasfee.ListSupportedChainsRequest) (*gasfee.ListSupportedChainsResponse, error) {
+	registry, err := ms.registry()
+	if err != nil {
+		return nil, err
+	}
+	chains := make([]*gasfee.SupportedChain, 0, len(registry.RPCs))
+	for _, rpc := range registry.RPCs {
+		chains = append(chains, &gasfee.SupportedChain{
+			ChainId:     rpc.ChainId,
+			ChainName:   rpc.ChainName,
+			NativeToken: rpc.NativeToken,
+			Decimal:     uint32(rpc.Decimal),
+		})
+	}
+	return &gasfee.ListSupportedChainsResponse{
+		ReturnCode: 100,
+		Message:    "list supported chains success",
+		Chains:     chains,
 	}, nil
 }
+
+func (ms *TokenPriceRpcService) ListSupportedTokens(ctx context.Context, in *gasfee.ListSupportedTokensRequest) (*gasfee.ListSupportedTokensResponse, error) {
+	registry, err := ms.registry()
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]*gasfee.SupportedToken, 0, len(registry.Symbols))
+	for _, symbol := range registry.Symbols {
+		tokens = append(tokens, &gasfee.SupportedToken{
+			Symbol:    symbol.Name,
+			Decimal:   uint32(symbol.Decimal),
+			Aliases:   symbol.Aliases,
+			Addresses: tokenAddresses(symbol.Addresses),
+		})
+	}
+	return &gasfee.ListSupportedTokensResponse{
+		ReturnCode:      100,
+		Message:         "list supported tokens success",
+		Tokens:          tokens,
+		QuoteCurrencies: ms.quoteCurrencies(),
+	}, nil
+}
+
+func (ms *TokenPriceRpcService) quoteCurrencies() []string {
+	if len(ms.QuoteCurrencies) == 0 {
+		return []string{config.DefaultQuoteCurrency}
+	}
+	return ms.QuoteCurrencies
+}
+
+// quoteCurrency resolves the quote currency a request asks for, the default
+// one when it names none.
+func (ms *TokenPriceRpcService) quoteCurrency(requested string) (string, error) {
+	quoteCurrencies := ms.quoteCurrencies()
+	if requested == "" {
+		return quoteCurrencies[0], nil
+	}
+	requested = strings.ToUpper(requested)
+	for _, quoteCurrency := range quoteCurrencies {
+		if quoteCurrency == requested {
+			return quoteCurrency, nil
+		}
+	}
+	return "", status.Errorf(codes.InvalidArgument, "unsupported quote currency %s, want one of %s", requested, strings.Join(quoteCurrencies, ", "))
+}
+
+// resolveSymbol returns the symbol of token on chainId, where token is a
+// symbol, one of its aliases or its contract address. Other names are looked
+// up as they are.
+func (ms *TokenPriceRpcService) resolveSymbol(chainId uint64, token string) (string, error) {
+	if symbol, ok := ms.symbolMap().ResolveToken(chainId, token); ok {
+		return symbol, nil
+	}
+	if common.IsHexAddress(token) {
+		return "", status.Errorf(codes.NotFound, "unknown token %s on chain %d", token, chainId)
+	}
+	return token, nil
+}
+
+// symbolMap returns the symbol map of the registry, rebuilt at most every
+// symbolMapTTL. While the registry cannot be read the previous map is kept.
+func (ms *TokenPriceRpcService) symbolMap() *symbols.Map {
+	ms.symbolMu.Lock()
+	defer ms.symbolMu.Unlock()
+	if ms.symbols != nil && time.Since(ms.symbolsAt) < symbolMapTTL {
+		return ms.symbols
+	}
+	registry := *ms.Registry
+	if err := ms.db.ApplyRegistry(&registry); err != nil {
+		log.Error("Query chain and token registry fail, keep the previous symbol map", "err", err)
+		if ms.symbols == nil {
+			ms.symbols = symbols.NewMap(ms.Registry.Symbols)
+		}
+	} else {
+		ms.symbols = symbols.NewMap(registry.Symbols)
+	}
+	ms.symbolsAt = time.Now()
+	return ms.symbols
+}
+
+// registry returns the yaml chains and symbols with the database registry merged in,
+// the same view the oracle runs from.
+func (ms *TokenPriceRpcService) registry() (*config.Config, error) {
+	registry := *ms.Registry
+	if err := ms.db.ApplyRegistry(&registry); err != nil {
+		log.Error("Query chain and token registry fail", "err", err)
+		return nil, status.Error(codes.Internal, "query registry fail")
+	}
+	return &registry, nil
+}
@@ -0,0 +1,280 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// RetryAfterMetadataKey is set on ResourceExhausted responses with the number
+// of seconds the caller should wait before retrying.
+const RetryAfterMetadataKey = "retry-after"
+
+const (
+	// bucketIdleTimeout is how long an untouched bucket is kept before it is swept.
+	bucketIdleTimeout = 10 * time.Minute
+	// quotaFlushInterval is how often the daily quota counts are written to
+	// the database and the counts of other replicas read back.
+	quotaFlushInterval = 5 * time.Second
+)
+
+type limit struct {
+	rate       float64
+	burst      int
+	dailyQuota uint64
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// take refills the bucket for the time elapsed since it was last seen and
+// consumes one token. When the bucket is empty it returns how long until the
+// next token is available.
+func (b *tokenBucket) take(l limit, now time.Time) (bool, time.Duration) {
+	b.tokens = math.Min(float64(l.burst), b.tokens+now.Sub(b.lastSeen).Seconds()*l.rate)
+	b.lastSeen = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// quotaDay names the daily quota count of one caller.
+type quotaDay struct {
+	quotaKey string
+	day      string
+}
+
+type quotaCount struct {
+	// stored is the count in the database as of the last flush, with the
+	// requests other replicas counted
+	stored uint64
+	// pending are the requests counted here since
+	pending uint64
+}
+
+// RateLimiter limits request rates per caller and counts daily quotas in
+// memory, flushing them to the database every quotaFlushInterval. A replica
+// learns the requests the others counted at its next flush. While the
+// database is unavailable the counts keep growing in memory and are flushed
+// once it is back, so quotas stay enforced on the requests each replica saw.
+type RateLimiter struct {
+	enable    bool
+	fallback  limit
+	consumers map[string]limit
+	db        *database.DB
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+
+	quotaMu sync.Mutex
+	quotas  map[quotaDay]*quotaCount
+
+	// the quota counts are flushed by a goroutine until Close
+	closeOnce sync.Once
+	flushStop chan struct{}
+	flushDone chan struct{}
+}
+
+func NewRateLimiter(conf config.RateLimit, db *database.DB) *RateLimiter {
+	fallback := limit{rate: conf.Rate, burst: conf.Burst, dailyQuota: conf.DailyQuota}
+	consumers := make(map[string]limit, len(conf.Consumers))
+	for _, consumer := range conf.Consumers {
+		consumers[consumer.Name] = limit{rate: consumer.Rate, burst: consumer.Burst, dailyQuota: consumer.DailyQuota}
+	}
+	rl := &RateLimiter{
+		enable:    conf.Enable,
+		fallback:  fallback,
+		consumers: consumers,
+		db:        db,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+		quotas:    make(map[quotaDay]*quotaCount),
+		flushStop: make(chan struct{}),
+		flushDone: make(chan struct{}),
+	}
+	go rl.flushQuotasLoop()
+	return rl
+}
+
+// Close flushes the quota counts a last time and stops the flusher.
+func (rl *RateLimiter) Close() {
+	rl.closeOnce.Do(func() {
+		close(rl.flushStop)
+	})
+	<-rl.flushDone
+}
+
+func (rl *RateLimiter) flushQuotasLoop() {
+	defer close(rl.flushDone)
+	ticker := time.NewTicker(quotaFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rl.flushStop:
+			rl.flushQuotas(time.Now())
+			return
+		case <-ticker.C:
+			rl.flushQuotas(time.Now())
+		}
+	}
+}
+
+// flushQuotas adds the pending counts to the database and reads back the
+// totals. Counts that fail to be stored stay pending for the next flush, and
+// idle ones of past days are dropped.
+func (rl *RateLimiter) flushQuotas(now time.Time) {
+	rl.quotaMu.Lock()
+	flushing := make(map[quotaDay]uint64, len(rl.quotas))
+	for key, count := range rl.quotas {
+		flushing[key] = count.pending
+		count.pending = 0
+	}
+	rl.quotaMu.Unlock()
+
+	today := now.UTC().Format("2006-01-02")
+	for key, pending := range flushing {
+		if pending == 0 {
+			rl.quotaMu.Lock()
+			if key.day != today && rl.quotas[key].pending == 0 {
+				delete(rl.quotas, key)
+			}
+			rl.quotaMu.Unlock()
+			continue
+		}
+		stored, err := rl.db.ApiQuota.IncreaseApiQuota(key.quotaKey, key.day, pending)
+
+		rl.quotaMu.Lock()
+		count := rl.quotas[key]
+		if err != nil {
+			log.Error("flush api quota fail, keep counting in memory", "quotaKey", key.quotaKey, "pending", pending, "err", err)
+			count.pending += pending
+		} else {
+			count.stored = stored
+		}
+		rl.quotaMu.Unlock()
+	}
+}
+
+// countQuota counts one more request for quotaKey on day and returns the
+// count of the day.
+func (rl *RateLimiter) countQuota(quotaKey string, day string) uint64 {
+	rl.quotaMu.Lock()
+	defer rl.quotaMu.Unlock()
+	key := quotaDay{quotaKey: quotaKey, day: day}
+	count, ok := rl.quotas[key]
+	if !ok {
+		count = &quotaCount{}
+		rl.quotas[key] = count
+	}
+	count.pending++
+	return count.stored + count.pending
+}
+
+func (rl *RateLimiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := rl.allow(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (rl *RateLimiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := rl.allow(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (rl *RateLimiter) allow(ctx context.Context) error {
+	if !rl.enable {
+		return nil
+	}
+
+	bucketKey, quotaKey, l := rl.resolve(ctx)
+	now := time.Now()
+
+	if l.rate > 0 && l.burst > 0 {
+		ok, wait := rl.take(bucketKey, l, now)
+		if !ok {
+			return exhausted(ctx, "rate limit exceeded", wait)
+		}
+	}
+
+	if l.dailyQuota > 0 {
+		if rl.countQuota(quotaKey, now.UTC().Format("2006-01-02")) > l.dailyQuota {
+			tomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+			return exhausted(ctx, "daily quota exceeded", tomorrow.Sub(now))
+		}
+	}
+	return nil
+}
+
+// resolve returns the in-memory bucket key, the persisted quota key and the
+// limit applying to the caller. Authenticated consumers are keyed by token and
+// named in the quota table; anonymous callers fall back to their peer IP.
+func (rl *RateLimiter) resolve(ctx context.Context) (string, string, limit) {
+	if consumer, ok := ConsumerFromContext(ctx); ok {
+		l, ok := rl.consumers[consumer.Name]
+		if !ok {
+			l = rl.fallback
+		}
+		return "token:" + consumer.Token, consumer.Name, l
+	}
+	ip := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return "ip:" + ip, "ip:" + ip, rl.fallback
+}
+
+func (rl *RateLimiter) take(key string, l limit, now time.Time) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) > bucketIdleTimeout {
+		for k, b := range rl.buckets {
+			if now.Sub(b.lastSeen) > bucketIdleTimeout {
+				delete(rl.buckets, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.burst), lastSeen: now}
+		rl.buckets[key] = bucket
+	}
+	return bucket.take(l, now)
+}
+
+func exhausted(ctx context.Context, msg string, wait time.Duration) error {
+	retryAfter := int64(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(RetryAfterMetadataKey, strconv.FormatInt(retryAfter, 10))); err != nil {
+		log.Warn("set retry-after header fail", "err", err)
+	}
+	return status.Errorf(codes.ResourceExhausted, "%s, retry after %ds", msg, retryAfter)
+}
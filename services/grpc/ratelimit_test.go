@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// flakyQuotas counts the quota writes and fails them while down.
+type flakyQuotas struct {
+	database.ApiQuotaDB
+
+	mu     sync.Mutex
+	down   bool
+	writes int
+}
+
+func (f *flakyQuotas) IncreaseApiQuota(quotaKey string, day string, count uint64) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes++
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	return f.ApiQuotaDB.IncreaseApiQuota(quotaKey, day, count)
+}
+
+func (f *flakyQuotas) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func TestQuotaIsCountedInMemoryAndFlushed(t *testing.T) {
+	db := database.NewMemoryDB()
+	quotas := &flakyQuotas{ApiQuotaDB: db.ApiQuota}
+	db.ApiQuota = quotas
+	rl := NewRateLimiter(config.RateLimit{Enable: true, DailyQuota: 5}, db)
+	defer rl.Close()
+	ctx := context.WithValue(context.Background(), consumerContextKey{}, &Consumer{Name: "a", Token: "a-token"})
+	now := time.Now()
+	day := now.UTC().Format("2006-01-02")
+
+	// another replica already counted two requests today
+	_, err := db.ApiQuota.IncreaseApiQuota("a", day, 2)
+	require.NoError(t, err)
+	quotas.writes = 0
+
+	require.NoError(t, rl.allow(ctx))
+	require.Zero(t, quotas.writes, "requests are not written one by one")
+	rl.flushQuotas(now)
+	apiQuota, err := db.ApiQuota.QueryApiQuota("a", day)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), apiQuota.RequestCount)
+
+	// the quota stays enforced while the database is down
+	quotas.setDown(true)
+	require.NoError(t, rl.allow(ctx))
+	require.NoError(t, rl.allow(ctx))
+	rl.flushQuotas(now)
+	require.Equal(t, codes.ResourceExhausted, status.Code(rl.allow(ctx)))
+
+	quotas.setDown(false)
+	rl.flushQuotas(now)
+	apiQuota, err = db.ApiQuota.QueryApiQuota("a", day)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), apiQuota.RequestCount, "the counts of the outage are flushed once it is back")
+
+	// an idle count of a past day is dropped
+	rl.flushQuotas(now.Add(24 * time.Hour))
+	require.Empty(t, rl.quotas)
+}
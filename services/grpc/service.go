@@ -2,23 +2,48 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	ethevent "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/cpchain-network/gas-oracle/common/symbols"
+	"github.com/cpchain-network/gas-oracle/config"
 	"github.com/cpchain-network/gas-oracle/database"
 	"github.com/cpchain-network/gas-oracle/proto/gasfee"
 )
 
 const MaxRecvMessageSize = 1024 * 1024 * 30000
 
+// symbolMapTTL is how long registry changes may take to reach symbol lookups.
+const symbolMapTTL = 30 * time.Second
+
+// DefaultStopTimeout bounds the graceful stop when the stop context carries no deadline.
+const DefaultStopTimeout = 30 * time.Second
+
 type TokenPriceRpcConfig struct {
-	Host string
-	Port int
+	Host      string
+	Port      int
+	HttpPort  int
+	TLS       config.TLS
+	Auth      config.Auth
+	Admin     config.Admin
+	RateLimit config.RateLimit
+	// Registry is the validated yaml config the registry tables are merged into
+	Registry *config.Config
+	// QuoteCurrencies are the currencies prices are served in, the first one
+	// answering requests that name none
+	QuoteCurrencies []string
+	// CacheTTL bounds the age of in-process lookups, DefaultCacheTTL when unset
+	CacheTTL time.Duration
 }
 
 type TokenPriceRpcService struct {
@@ -26,49 +51,131 @@ type TokenPriceRpcService struct {
 
 	db *database.DB
 
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	server             *grpc.Server
+	consumerAuth       *ConsumerAuth
+	rateLimiter        *RateLimiter
+	cache              *latestCache
+	eventSubs          []ethevent.Subscription
+	gateway            *http.Server
+
+	// symbols resolves requested names and addresses, rebuilt from the registry every symbolMapTTL
+	symbolMu  sync.Mutex
+	symbols   *symbols.Map
+	symbolsAt time.Time
+
 	gasfee.UnimplementedTokenGasPriceServicesServer
 	stopped atomic.Bool
 }
 
 func NewTokenPriceRpcService(conf *TokenPriceRpcConfig, db *database.DB) (*TokenPriceRpcService, error) {
+	adminAuth := NewAdminAuth(conf.Admin)
+	consumerAuth := NewConsumerAuth(conf.Auth, db)
+	rateLimiter := NewRateLimiter(conf.RateLimit, db)
 	return &TokenPriceRpcService{
 		TokenPriceRpcConfig: conf,
 		db:                  db,
+		consumerAuth:        consumerAuth,
+		rateLimiter:         rateLimiter,
+		unaryInterceptors: []grpc.UnaryServerInterceptor{
+			ClientIdentityUnaryInterceptor,
+			adminAuth.UnaryInterceptor,
+			consumerAuth.UnaryInterceptor,
+			rateLimiter.UnaryInterceptor,
+		},
+		streamInterceptors: []grpc.StreamServerInterceptor{
+			ClientIdentityStreamInterceptor,
+			consumerAuth.StreamInterceptor,
+			rateLimiter.StreamInterceptor,
+		},
 	}, nil
 }
 
 func (ms *TokenPriceRpcService) Start(ctx context.Context) error {
-	go func(ms *TokenPriceRpcService) {
-		rpcAddr := fmt.Sprintf("%s:%d", ms.TokenPriceRpcConfig.Host, ms.TokenPriceRpcConfig.Port)
-		listener, err := net.Listen("tcp", rpcAddr)
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(MaxRecvMessageSize),
+		grpc.ChainUnaryInterceptor(ms.unaryInterceptors...),
+		grpc.ChainStreamInterceptor(ms.streamInterceptors...),
+	}
+	if ms.TokenPriceRpcConfig.TLS.CertFile != "" {
+		reloader, err := newCertReloader(ms.TokenPriceRpcConfig.TLS)
 		if err != nil {
-			log.Error("Could not start tcp listener. ")
+			return fmt.Errorf("failed to load tls config: %w", err)
 		}
-
-		opt := grpc.MaxRecvMsgSize(MaxRecvMessageSize)
-
-		gs := grpc.NewServer(
-			opt,
-			grpc.ChainUnaryInterceptor(
-				nil,
-			),
-		)
-
-		reflection.Register(gs)
-		gasfee.RegisterTokenGasPriceServicesServer(gs, ms)
-
+		opts = append(opts, grpc.Creds(reloader.TransportCredentials()))
+		log.Info("grpc tls enabled", "cert", ms.TokenPriceRpcConfig.TLS.CertFile, "mtls", ms.TokenPriceRpcConfig.TLS.ClientCAFile != "")
+	}
+
+	rpcAddr := fmt.Sprintf("%s:%d", ms.TokenPriceRpcConfig.Host, ms.TokenPriceRpcConfig.Port)
+	listener, err := net.Listen("tcp", rpcAddr)
+	if err != nil {
+		return fmt.Errorf("could not start tcp listener on %s: %w", rpcAddr, err)
+	}
+
+	gs := grpc.NewServer(opts...)
+	reflection.Register(gs)
+	gasfee.RegisterTokenGasPriceServicesServer(gs, ms)
+	if len(ms.TokenPriceRpcConfig.Admin.Tokens) > 0 {
+		gasfee.RegisterGasOracleAdminServicesServer(gs, NewAdminService(ms.db, ms.Registry))
+	}
+	ms.server = gs
+
+	go func() {
 		log.Info("grpc info", "addr", listener.Addr())
-
-		if err := gs.Serve(listener); err != nil {
+		if err := gs.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 			log.Error("start rpc server fail", "err", err)
 		}
-	}(ms)
+	}()
+
+	if ms.TokenPriceRpcConfig.HttpPort != 0 {
+		if err := ms.startGateway(); err != nil {
+			gs.Stop()
+			listener.Close()
+			ms.server = nil
+			return err
+		}
+	}
 	return nil
 }
 
+// Stop drains in-flight requests and streams before closing the listeners.
+// When ctx expires first, remaining connections are closed forcefully.
 func (ms *TokenPriceRpcService) Stop(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultStopTimeout)
+		defer cancel()
+	}
+
+	var result error
+	if ms.gateway != nil {
+		if err := ms.gateway.Shutdown(ctx); err != nil {
+			result = fmt.Errorf("failed to stop http gateway: %w", err)
+		}
+	}
+
+	if ms.server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			ms.server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			log.Warn("grpc graceful stop timed out, closing remaining connections")
+			ms.server.Stop()
+			<-stopped
+		}
+	}
+
+	ms.consumerAuth.Close()
+	ms.rateLimiter.Close()
+	ms.unsubscribeEvents()
 	ms.stopped.Store(true)
-	return nil
+	log.Info("grpc server stopped")
+	return result
 }
 
 func (ms *TokenPriceRpcService) Stopped() bool {
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestStartReleasesGrpcPortWhenGatewayFails(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	grpcPort := freePort(t)
+	ms, err := NewTokenPriceRpcService(&TokenPriceRpcConfig{
+		Host:     "127.0.0.1",
+		Port:     grpcPort,
+		HttpPort: taken.Addr().(*net.TCPAddr).Port,
+	}, database.NewMemoryDB())
+	require.NoError(t, err)
+	defer ms.consumerAuth.Close()
+
+	require.Error(t, ms.Start(context.Background()))
+	require.Nil(t, ms.server)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", grpcPort))
+	require.NoError(t, err, "the grpc listener is closed")
+	listener.Close()
+}
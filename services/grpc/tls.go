@@ -0,0 +1,181 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/cpchain-network/gas-oracle/config"
+)
+
+// reloadCheckInterval bounds how often the certificate files are stat'ed for changes.
+const reloadCheckInterval = 5 * time.Second
+
+// ClientIdentity is the verified client certificate of an mTLS connection.
+type ClientIdentity struct {
+	CommonName string
+	DNSNames   []string
+	URIs       []string
+}
+
+type clientIdentityContextKey struct{}
+
+// ClientIdentityFromContext returns the mTLS client identity of the current request.
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityContextKey{}).(*ClientIdentity)
+	return identity, ok
+}
+
+// certReloader serves the server certificate and client CA pool, reloading
+// them when the files on disk change so certificates can be rotated without
+// a restart.
+type certReloader struct {
+	conf config.TLS
+
+	mu        sync.Mutex
+	base      *tls.Config
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+func newCertReloader(conf config.TLS) (*certReloader, error) {
+	cr := &certReloader{conf: conf}
+	if err := cr.reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func (cr *certReloader) files() []string {
+	files := []string{cr.conf.CertFile, cr.conf.KeyFile}
+	if cr.conf.ClientCAFile != "" {
+		files = append(files, cr.conf.ClientCAFile)
+	}
+	return files
+}
+
+func (cr *certReloader) reload() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range cr.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(cr.conf.CertFile, cr.conf.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	base := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	}
+	if cr.conf.ClientCAFile != "" {
+		caPem, err := os.ReadFile(cr.conf.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return fmt.Errorf("no certificates found in client ca %s", cr.conf.ClientCAFile)
+		}
+		base.ClientCAs = pool
+		base.ClientAuth = tls.VerifyClientCertIfGiven
+		if cr.conf.RequireClientCert {
+			base.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	cr.base = base
+	cr.modTimes = modTimes
+	return nil
+}
+
+func (cr *certReloader) changed() bool {
+	for _, file := range cr.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			// a file is briefly missing while it is being replaced
+			return false
+		}
+		if !info.ModTime().Equal(cr.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cr *certReloader) GetConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if now := time.Now(); now.Sub(cr.lastCheck) > reloadCheckInterval {
+		cr.lastCheck = now
+		if cr.changed() {
+			if err := cr.reload(); err != nil {
+				log.Error("reload tls certificates fail, keep serving the previous ones", "err", err)
+			} else {
+				log.Info("tls certificates reloaded", "cert", cr.conf.CertFile)
+			}
+		}
+	}
+	return cr.base, nil
+}
+
+// TransportCredentials returns gRPC server credentials backed by the reloader.
+func (cr *certReloader) TransportCredentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: cr.GetConfigForClient,
+	})
+}
+
+func clientIdentityFromPeer(ctx context.Context) (*ClientIdentity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	identity := &ClientIdentity{
+		CommonName: cert.Subject.CommonName,
+		DNSNames:   cert.DNSNames,
+	}
+	for _, uri := range cert.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+	return identity, true
+}
+
+func ClientIdentityUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if identity, ok := clientIdentityFromPeer(ctx); ok {
+		ctx = context.WithValue(ctx, clientIdentityContextKey{}, identity)
+	}
+	return handler(ctx, req)
+}
+
+func ClientIdentityStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	identity, ok := clientIdentityFromPeer(ss.Context())
+	if !ok {
+		return handler(srv, ss)
+	}
+	return handler(srv, &wrappedServerStream{
+		ServerStream: ss,
+		ctx:          context.WithValue(ss.Context(), clientIdentityContextKey{}, identity),
+	})
+}
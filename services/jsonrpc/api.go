@@ -0,0 +1,165 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/synchronizer"
+)
+
+var (
+	errNoEstimate     = errors.New("no fee estimate for chain yet")
+	errNoFeeHistory   = errors.New("no fee history for chain yet")
+	errBadPercentiles = errors.New("reward percentiles must be ascending values in [0, 100]")
+)
+
+// priorityFeeKnots are the percentiles stored per block in gas_fee_history.
+var priorityFeeKnots = []float64{0, 25, 50, 75, 100}
+
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// ethAPI answers the fee related subset of the eth namespace for one chain
+// from the estimates stored by the synchronizer.
+type ethAPI struct {
+	chainId uint64
+	db      *database.DB
+}
+
+func (api *ethAPI) ChainId() hexutil.Uint64 {
+	return hexutil.Uint64(api.chainId)
+}
+
+func (api *ethAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	gasFee, err := api.db.GasFee.QueryGasFees(strconv.FormatUint(api.chainId, 10))
+	if err != nil || gasFee.GasPrice == nil {
+		return nil, errNoEstimate
+	}
+	return (*hexutil.Big)(gasFee.GasPrice), nil
+}
+
+func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	gasFee, err := api.db.GasFee.QueryGasFees(strconv.FormatUint(api.chainId, 10))
+	if err != nil || gasFee.PriorityFee == nil {
+		return nil, errNoEstimate
+	}
+	return (*hexutil.Big)(gasFee.PriorityFee), nil
+}
+
+// FeeHistory serves the stored blocks at or below lastBlock. The synchronizer
+// samples blocks, so only the newest run of consecutive blocks is returned and
+// the result may hold fewer blocks than asked for. Rewards are interpolated
+// between the stored priority fee percentiles.
+func (api *ethAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p < rewardPercentiles[i-1]) {
+			return nil, errBadPercentiles
+		}
+	}
+	if blockCount == 0 {
+		return &feeHistoryResult{OldestBlock: (*hexutil.Big)(big.NewInt(0))}, nil
+	}
+	if blockCount > synchronizer.FeeHistoryWindow {
+		blockCount = synchronizer.FeeHistoryWindow
+	}
+
+	var newestBlock *big.Int
+	if lastBlock >= 0 {
+		newestBlock = big.NewInt(lastBlock.Int64())
+	}
+	histories, err := api.db.GasFeeHistory.QueryGasFeeHistories(strconv.FormatUint(api.chainId, 10), newestBlock, int(blockCount))
+	if err != nil {
+		log.Error("query gas fee history fail", "chainId", api.chainId, "err", err)
+		return nil, fmt.Errorf("query fee history fail")
+	}
+	histories = contiguousTail(histories)
+	if len(histories) == 0 {
+		return nil, errNoFeeHistory
+	}
+
+	result := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(histories[0].BlockNumber),
+		BaseFee:      make([]*hexutil.Big, 0, len(histories)+1),
+		GasUsedRatio: make([]float64, 0, len(histories)),
+	}
+	if len(rewardPercentiles) > 0 {
+		result.Reward = make([][]*hexutil.Big, 0, len(histories))
+	}
+	var lastRatio float64
+	for _, history := range histories {
+		ratio, _ := strconv.ParseFloat(history.GasUsedRatio, 64)
+		lastRatio = ratio
+		result.BaseFee = append(result.BaseFee, (*hexutil.Big)(history.BaseFee))
+		result.GasUsedRatio = append(result.GasUsedRatio, ratio)
+		if len(rewardPercentiles) > 0 {
+			knots := []*big.Int{history.PriorityFeeMin, history.PriorityFeeP25, history.PriorityFeeP50, history.PriorityFeeP75, history.PriorityFeeMax}
+			reward := make([]*hexutil.Big, len(rewardPercentiles))
+			for i, p := range rewardPercentiles {
+				reward[i] = (*hexutil.Big)(interpolate(knots, p))
+			}
+			result.Reward = append(result.Reward, reward)
+		}
+	}
+	newestBaseFee := histories[len(histories)-1].BaseFee
+	result.BaseFee = append(result.BaseFee, (*hexutil.Big)(nextBaseFee(newestBaseFee, lastRatio)))
+	return result, nil
+}
+
+// contiguousTail returns the newest run of consecutive blocks in histories,
+// which are sorted by ascending block number.
+func contiguousTail(histories []database.GasFeeHistory) []database.GasFeeHistory {
+	if len(histories) == 0 {
+		return histories
+	}
+	start := len(histories) - 1
+	for start > 0 {
+		prev, next := histories[start-1].BlockNumber, histories[start].BlockNumber
+		if prev == nil || next == nil || new(big.Int).Sub(next, prev).Cmp(big.NewInt(1)) != 0 {
+			break
+		}
+		start--
+	}
+	return histories[start:]
+}
+
+// interpolate linearly interpolates percentile p between the stored knots.
+func interpolate(knots []*big.Int, p float64) *big.Int {
+	for i := 1; i < len(priorityFeeKnots); i++ {
+		if p > priorityFeeKnots[i] {
+			continue
+		}
+		lo, hi := knots[i-1], knots[i]
+		if lo == nil || hi == nil {
+			return big.NewInt(0)
+		}
+		weight := (p - priorityFeeKnots[i-1]) / (priorityFeeKnots[i] - priorityFeeKnots[i-1])
+		delta := new(big.Float).Mul(new(big.Float).SetInt(new(big.Int).Sub(hi, lo)), big.NewFloat(weight))
+		d, _ := delta.Add(delta, big.NewFloat(0.5)).Int(nil)
+		return new(big.Int).Add(lo, d)
+	}
+	return new(big.Int).Set(knots[len(knots)-1])
+}
+
+// nextBaseFee estimates the base fee of the block after one with the given
+// base fee and gas used ratio, following EIP-1559 with the target at half the limit.
+func nextBaseFee(baseFee *big.Int, gasUsedRatio float64) *big.Int {
+	if baseFee == nil {
+		return big.NewInt(0)
+	}
+	factor := big.NewFloat(1 + (gasUsedRatio*2-1)/8)
+	next, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), factor).Int(nil)
+	return next
+}
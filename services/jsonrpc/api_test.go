@@ -0,0 +1,99 @@
+package jsonrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+func knots(values ...int64) []*big.Int {
+	result := make([]*big.Int, len(values))
+	for i, v := range values {
+		result[i] = big.NewInt(v)
+	}
+	return result
+}
+
+func TestInterpolate(t *testing.T) {
+	k := knots(0, 100, 200, 400, 800)
+	for _, tc := range []struct {
+		p    float64
+		want int64
+	}{
+		{0, 0},
+		{10, 40},
+		{25, 100},
+		{50, 200},
+		{60, 280},
+		{75, 400},
+		{90, 640},
+		{100, 800},
+	} {
+		require.Equal(t, tc.want, interpolate(k, tc.p).Int64(), "p%v", tc.p)
+	}
+	require.Equal(t, int64(0), interpolate([]*big.Int{nil, nil, nil, nil, nil}, 50).Int64())
+}
+
+func TestNextBaseFee(t *testing.T) {
+	require.Equal(t, int64(1125), nextBaseFee(big.NewInt(1000), 1).Int64())
+	require.Equal(t, int64(1000), nextBaseFee(big.NewInt(1000), 0.5).Int64())
+	require.Equal(t, int64(875), nextBaseFee(big.NewInt(1000), 0).Int64())
+	require.Equal(t, int64(0), nextBaseFee(nil, 1).Int64())
+}
+
+func newTestAPI(t *testing.T, blocks ...int64) *ethAPI {
+	db := database.NewMemoryDB()
+	histories := make([]database.GasFeeHistory, 0, len(blocks))
+	for _, block := range blocks {
+		histories = append(histories, database.GasFeeHistory{
+			ChainId:        big.NewInt(1),
+			BlockNumber:    big.NewInt(block),
+			BaseFee:        big.NewInt(block * 10),
+			GasUsedRatio:   "0.5",
+			PriorityFeeMin: big.NewInt(0),
+			PriorityFeeP25: big.NewInt(100),
+			PriorityFeeP50: big.NewInt(200),
+			PriorityFeeP75: big.NewInt(400),
+			PriorityFeeMax: big.NewInt(800),
+		})
+	}
+	require.NoError(t, db.GasFeeHistory.StoreGasFeeHistories(histories))
+	return &ethAPI{chainId: 1, db: db}
+}
+
+func TestFeeHistoryPercentiles(t *testing.T) {
+	api := newTestAPI(t, 100, 101, 102)
+	result, err := api.FeeHistory(context.Background(), 3, rpc.LatestBlockNumber, []float64{10, 50, 90})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.OldestBlock.ToInt().Int64())
+	require.Len(t, result.Reward, 3)
+	for _, reward := range result.Reward {
+		require.Equal(t, int64(40), reward[0].ToInt().Int64())
+		require.Equal(t, int64(200), reward[1].ToInt().Int64())
+		require.Equal(t, int64(640), reward[2].ToInt().Int64())
+	}
+	require.Len(t, result.BaseFee, 4)
+	require.Equal(t, int64(1020), result.BaseFee[3].ToInt().Int64())
+
+	_, err = api.FeeHistory(context.Background(), 3, rpc.LatestBlockNumber, []float64{50, 10})
+	require.ErrorIs(t, err, errBadPercentiles)
+}
+
+func TestFeeHistoryReturnsContiguousTail(t *testing.T) {
+	api := newTestAPI(t, 90, 95, 96, 100, 101, 102)
+	result, err := api.FeeHistory(context.Background(), 6, rpc.LatestBlockNumber, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.OldestBlock.ToInt().Int64())
+	require.Len(t, result.GasUsedRatio, 3)
+	require.Equal(t, int64(1000), result.BaseFee[0].ToInt().Int64())
+
+	result, err = api.FeeHistory(context.Background(), 6, rpc.BlockNumber(99), nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(95), result.OldestBlock.ToInt().Int64())
+	require.Len(t, result.GasUsedRatio, 2)
+}
@@ -0,0 +1,161 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+// chainListTTL is how long registry changes may take to reach the served chains.
+const chainListTTL = 30 * time.Second
+
+type FeeRpcConfig struct {
+	Host string
+	Port int
+	// Registry is the validated yaml config the registry tables are merged into
+	Registry *config.Config
+}
+
+// FeeRpcService is an Ethereum JSON-RPC facade serving each chain of the
+// registry under /rpc/{chainId}.
+type FeeRpcService struct {
+	*FeeRpcConfig
+
+	db         *database.DB
+	httpServer *http.Server
+
+	// servers holds the eth api of each chain requested so far, and chains
+	// the chain ids served, rebuilt from the registry every chainListTTL
+	mu       sync.Mutex
+	servers  map[uint64]*rpc.Server
+	chains   map[uint64]bool
+	chainsAt time.Time
+
+	stopped atomic.Bool
+}
+
+func NewFeeRpcService(conf *FeeRpcConfig, db *database.DB) (*FeeRpcService, error) {
+	return &FeeRpcService{
+		FeeRpcConfig: conf,
+		db:           db,
+		servers:      make(map[uint64]*rpc.Server),
+	}, nil
+}
+
+func (fs *FeeRpcService) Start(ctx context.Context) error {
+	rpcAddr := fmt.Sprintf("%s:%d", fs.Host, fs.Port)
+	listener, err := net.Listen("tcp", rpcAddr)
+	if err != nil {
+		return fmt.Errorf("could not start json rpc listener: %w", err)
+	}
+
+	fs.httpServer = &http.Server{Handler: fs.handler(), ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		log.Info("json rpc info", "addr", listener.Addr())
+		if err := fs.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("start json rpc server fail", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (fs *FeeRpcService) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc/{chainId}", func(w http.ResponseWriter, r *http.Request) {
+		chainId, err := strconv.ParseUint(r.PathValue("chainId"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid chain id", http.StatusBadRequest)
+			return
+		}
+		server, err := fs.server(chainId)
+		if err != nil {
+			log.Error("register eth api fail", "chainId", chainId, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if server == nil {
+			http.Error(w, "unsupported chain id", http.StatusNotFound)
+			return
+		}
+		server.ServeHTTP(w, r)
+	})
+	return mux
+}
+
+// server returns the eth api of chainId, nil when the registry has no such chain.
+func (fs *FeeRpcService) server(chainId uint64) (*rpc.Server, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if !fs.chainList()[chainId] {
+		return nil, nil
+	}
+	if server, ok := fs.servers[chainId]; ok {
+		return server, nil
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethAPI{chainId: chainId, db: fs.db}); err != nil {
+		return nil, fmt.Errorf("failed to register eth api for chain %d: %w", chainId, err)
+	}
+	fs.servers[chainId] = server
+	return server, nil
+}
+
+// chainList returns the chain ids of the registry, fs.mu held. While the
+// registry cannot be read the previous list is kept.
+func (fs *FeeRpcService) chainList() map[uint64]bool {
+	if fs.chains != nil && time.Since(fs.chainsAt) < chainListTTL {
+		return fs.chains
+	}
+	registry := *fs.Registry
+	if err := fs.db.ApplyRegistry(&registry); err != nil {
+		log.Error("Query chain and token registry fail, keep the previous chain list", "err", err)
+		if fs.chains == nil {
+			fs.chains = chainIds(fs.Registry.RPCs)
+		}
+	} else {
+		fs.chains = chainIds(registry.RPCs)
+	}
+	fs.chainsAt = time.Now()
+	return fs.chains
+}
+
+func chainIds(rpcs []*config.RPC) map[uint64]bool {
+	chains := make(map[uint64]bool, len(rpcs))
+	for _, rpc := range rpcs {
+		chains[rpc.ChainId] = true
+	}
+	return chains
+}
+
+func (fs *FeeRpcService) Stop(ctx context.Context) error {
+	var result error
+	if fs.httpServer != nil {
+		if err := fs.httpServer.Shutdown(ctx); err != nil {
+			result = fmt.Errorf("failed to stop json rpc server: %w", err)
+		}
+	}
+	fs.mu.Lock()
+	for _, server := range fs.servers {
+		server.Stop()
+	}
+	fs.mu.Unlock()
+	fs.stopped.Store(true)
+	return result
+}
+
+func (fs *FeeRpcService) Stopped() bool {
+	return fs.stopped.Load()
+}
@@ -0,0 +1,53 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+func postRpc(t *testing.T, handler http.Handler, chainId string, method string) (int, string) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/rpc/"+chainId, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":[]}`))
+	request.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(recorder, request)
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestServesChainsOfTheRegistry(t *testing.T) {
+	db := database.NewMemoryDB()
+	registry := &config.Config{
+		LoopInternal: 5 * time.Second,
+		BackOffset:   2,
+		SkyeyeUrl:    "http://skyeye",
+		Symbols:      []config.Symbols{{Name: "eth", Decimal: 18}},
+		RPCs:         []*config.RPC{{RpcUrl: "http://eth", ChainId: 1, NativeToken: "ETH", Decimal: 18}},
+	}
+	require.NoError(t, registry.Validate())
+	require.NoError(t, db.TokenConfig.StoreOrUpdateTokenConfig(&database.TokenConfig{Symbol: "bnb", Decimal: 18, Enabled: true}))
+	require.NoError(t, db.ChainConfig.StoreOrUpdateChainConfig(&database.ChainConfig{
+		ChainId: big.NewInt(56), Enabled: true, RpcUrls: []string{"http://bsc"}, NativeToken: "BNB", Decimal: 18,
+	}))
+	require.NoError(t, db.GasFee.StoreOrUpdateGasFee(&database.GasFee{ChainId: big.NewInt(56), GasPrice: big.NewInt(3000000000)}))
+
+	fs, err := NewFeeRpcService(&FeeRpcConfig{Registry: registry}, db)
+	require.NoError(t, err)
+	handler := fs.handler()
+
+	code, body := postRpc(t, handler, "1", "eth_chainId")
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, body, `"result":"0x1"`)
+	code, body = postRpc(t, handler, "56", "eth_gasPrice")
+	require.Equal(t, http.StatusOK, code, "a chain of the database registry is served")
+	require.Contains(t, body, `"result":"0xb2d05e00"`)
+	code, _ = postRpc(t, handler, "97", "eth_chainId")
+	require.Equal(t, http.StatusNotFound, code)
+}
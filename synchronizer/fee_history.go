@@ -0,0 +1,43 @@
+package synchronizer
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+// FeeHistoryWindow is the number of blocks kept per chain for eth_feeHistory.
+const FeeHistoryWindow = 1024
+
+func gasUsedRatio(gasUsed, gasLimit uint64) string {
+	if gasLimit == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(gasUsed)/float64(gasLimit), 'f', -1, 64)
+}
+
+// setPriorityFeePercentiles records the 0/25/50/75/100 percentiles of the
+// block's priority fees, all zero for an empty block.
+func setPriorityFeePercentiles(history *database.GasFeeHistory, priorityFees []*big.Int) {
+	if len(priorityFees) == 0 {
+		history.PriorityFeeMin = big.NewInt(0)
+		history.PriorityFeeP25 = big.NewInt(0)
+		history.PriorityFeeP50 = big.NewInt(0)
+		history.PriorityFeeP75 = big.NewInt(0)
+		history.PriorityFeeMax = big.NewInt(0)
+		return
+	}
+	sort.Slice(priorityFees, func(i, j int) bool {
+		return priorityFees[i].Cmp(priorityFees[j]) < 0
+	})
+	at := func(p int) *big.Int {
+		return priorityFees[(len(priorityFees)-1)*p/100]
+	}
+	history.PriorityFeeMin = at(0)
+	history.PriorityFeeP25 = at(25)
+	history.PriorityFeeP50 = at(50)
+	history.PriorityFeeP75 = at(75)
+	history.PriorityFeeMax = at(100)
+}
@@ -36,6 +36,8 @@ type EthClient interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 	TxByHash(ctx context.Context, hash common.Hash) (*types.Transaction, error)
 	BlockDetailByNumber(ctx context.Context, number *big.Int) ([]string, *big.Int, error)
+	BlockFeeDetailByNumber(ctx context.Context, number *big.Int) (*BlockFeeDetail, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 	Close()
 }
 
@@ -50,12 +52,12 @@ func DialEthClient(ctx context.Context, rpcUrl string) (EthClient, error) {
 	bOff := retry.Exponential()
 	rpcClient, err := retry.Do(ctx, defaultDialAttempts, bOff, func() (*rpc.Client, error) {
 		if !IsURLAvailable(rpcUrl) {
-			return nil, fmt.Errorf("address unavailable (%s)", rpcUrl)
+			return nil, fmt.Errorf("address unavailable (%s)", RedactURL(rpcUrl))
 		}
 
 		client, err := rpc.DialContext(ctx, rpcUrl)
 		if err != nil {
-			return nil, fmt.Errorf("failed to dial address (%s): %w", rpcUrl, err)
+			return nil, fmt.Errorf("failed to dial address (%s): %w", RedactURL(rpcUrl), err)
 		}
 
 		return client, nil
@@ -95,8 +97,7 @@ func (c *clnt) GetLatestBlock(ctx context.Context) (*big.Int, error) {
 
 	blockHeight, err := c.BlockNumber(ctxwt)
 	if err != nil {
-		panic(fmt.Errorf("cannot retrieve the current chain ID: %w", err))
-		return nil, err
+		return nil, fmt.Errorf("cannot retrieve the latest block number: %w", err)
 	}
 	return big.NewInt(int64(blockHeight)), nil
 }
@@ -132,9 +133,20 @@ func (c *clnt) TxByHash(ctx context.Context, hash common.Hash) (*types.Transacti
 }
 
 type rpcBlock struct {
-	Hash         common.Hash `json:"hash"`
-	Transactions []string    `json:"transactions"`
-	BaseFee      string      `json:"baseFeePerGas"`
+	Hash         common.Hash    `json:"hash"`
+	Number       *hexutil.Big   `json:"number"`
+	Transactions []string       `json:"transactions"`
+	BaseFee      string         `json:"baseFeePerGas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+}
+
+type BlockFeeDetail struct {
+	Number       *big.Int
+	Transactions []string
+	BaseFee      *big.Int
+	GasUsed      uint64
+	GasLimit     uint64
 }
 
 func (c *clnt) BlockDetailByNumber(ctx context.Context, number *big.Int) ([]string, *big.Int, error) {
@@ -160,6 +172,41 @@ func (c *clnt) BlockDetailByNumber(ctx context.Context, number *big.Int) ([]stri
 	return block.Transactions, BaseFeeB, nil
 }
 
+func (c *clnt) BlockFeeDetailByNumber(ctx context.Context, number *big.Int) (*BlockFeeDetail, error) {
+	ctxwt, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+	defer cancel()
+
+	var block *rpcBlock
+	err := c.rpc.CallContext(ctxwt, &block, "eth_getBlockByNumber", toBlockNumArg(number), false)
+	if err != nil {
+		log.Error("Call eth_getBlockByNumber method fail", "err", err)
+		return nil, err
+	} else if block == nil {
+		log.Warn("block not found", "number", number)
+		return nil, ethereum.NotFound
+	}
+
+	baseFee := big.NewInt(0)
+	if block.BaseFee != "" {
+		baseFee, err = hexutil.DecodeBig(block.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base fee %s: %w", block.BaseFee, err)
+		}
+	}
+	blockNumber := number
+	if block.Number != nil {
+		blockNumber = block.Number.ToInt()
+	}
+
+	return &BlockFeeDetail{
+		Number:       blockNumber,
+		Transactions: block.Transactions,
+		BaseFee:      baseFee,
+		GasUsed:      uint64(block.GasUsed),
+		GasLimit:     uint64(block.GasLimit),
+	}, nil
+}
+
 func (c *clnt) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	var hex hexutil.Bytes
 	err := c.rpc.CallContext(ctx, &hex, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber))
@@ -253,6 +300,24 @@ func IsURLAvailable(address string) bool {
 	return true
 }
 
+// RedactURL hides credentials in a rpc url before it is logged. Providers
+// commonly embed the api key in the path or query, so only the scheme and host
+// are kept.
+func RedactURL(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	redacted := u.Scheme + "://" + u.Host
+	if u.User != nil {
+		redacted = u.Scheme + "://***@" + u.Host
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redacted += "/***"
+	}
+	return redacted
+}
+
 func DialEthClientWithTimeout(ctx context.Context, url string, disableHTTP2 bool) (
 	*ethclient.Client, error) {
 	ctxt, cancel := context.WithTimeout(ctx, defaultDialTimeout)
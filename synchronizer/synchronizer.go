@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"math/big"
+	"strconv"
 	"sync/atomic"
 	"time"
 
@@ -14,16 +15,19 @@ import (
 
 	"github.com/cpchain-network/gas-oracle/common/tasks"
 	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/event"
 	"github.com/cpchain-network/gas-oracle/synchronizer/node"
 )
 
 type OracleSynchronizer struct {
-	loopInternal   time.Duration
-	db             *database.DB
-	ethClient      node.EthClient
-	blockOffset    uint64
-	chainId        uint64
-	nativeToken    string
+	loopInternal time.Duration
+	db           *database.DB
+	bus          *event.Bus
+	ethClient    node.EthClient
+	blockOffset  uint64
+	chainId      uint64
+	// nativeSymbol is the symbol of the native token, priced by the worker
+	nativeSymbol   string
 	decimal        uint8
 	stopped        atomic.Bool
 	resourceCtx    context.Context
@@ -32,23 +36,26 @@ type OracleSynchronizer struct {
 }
 
 func (os *OracleSynchronizer) Stop(ctx context.Context) error {
+	os.resourceCancel()
+	err := os.tasks.Wait()
 	os.stopped.Store(true)
-	return nil
+	return err
 }
 
 func (os *OracleSynchronizer) Stopped() bool {
 	return os.stopped.Load()
 }
 
-func NewOracleSynchronizer(db *database.DB, client node.EthClient, blockOffset uint64, chainId uint64, nativeToken string, decimal uint8, loopInternal time.Duration, shutdown context.CancelCauseFunc) (*OracleSynchronizer, error) {
+func NewOracleSynchronizer(db *database.DB, bus *event.Bus, client node.EthClient, blockOffset uint64, chainId uint64, nativeSymbol string, decimal uint8, loopInternal time.Duration, shutdown context.CancelCauseFunc) (*OracleSynchronizer, error) {
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 
 	return &OracleSynchronizer{
 		loopInternal: loopInternal,
 		db:           db,
+		bus:          bus,
 		chainId:      chainId,
-		nativeToken:  nativeToken,
+		nativeSymbol: nativeSymbol,
 		decimal:      decimal,
 		ethClient:    client,
 		blockOffset:  blockOffset,
@@ -63,37 +70,65 @@ func NewOracleSynchronizer(db *database.DB, client node.EthClient, blockOffset u
 func (os *OracleSynchronizer) Start(ctx context.Context) error {
 	l1FeeTicker := time.NewTicker(os.loopInternal)
 	os.tasks.Go(func() error {
-		for range l1FeeTicker.C {
-			fee, err := os.processTokenPrice(os.chainId)
+		defer l1FeeTicker.Stop()
+		for {
+			select {
+			case <-os.resourceCtx.Done():
+				log.Info("oracle synchronizer stopped", "chainId", os.chainId)
+				return nil
+			case <-l1FeeTicker.C:
+			}
+			estimate, err := os.processTokenPrice(os.chainId)
 			if err != nil {
 				log.Error("process token price error", "err", err)
 				log.Error(err.Error())
+				continue
 			}
-			log.Info("get gas fee", "fee", fee, "chainId", os.chainId)
+			log.Info("get gas fee", "fee", estimate.fee, "chainId", os.chainId)
 			gasFee := &database.GasFee{
-				GUID:       uuid.New(),
-				ChainId:    big.NewInt(int64(os.chainId)),
-				Decimal:    os.decimal,
-				TokenName:  os.nativeToken,
-				PredictFee: fee.String(),
-				Timestamp:  uint64(time.Now().Unix()),
+				GUID:        uuid.New(),
+				ChainId:     big.NewInt(int64(os.chainId)),
+				Decimal:     os.decimal,
+				TokenName:   os.nativeSymbol,
+				PredictFee:  estimate.fee,
+				BaseFee:     estimate.baseFee,
+				GasPrice:    estimate.gasPrice,
+				PriorityFee: estimate.priorityFee,
+				Timestamp:   uint64(time.Now().Unix()),
 			}
 			err = os.db.GasFee.StoreOrUpdateGasFee(gasFee)
 			if err != nil {
 				log.Error("Oracle synchronizer store or update gas fee fail", "err", err)
 				return err
 			}
+			os.bus.PublishGasFee(gasFee)
+			if err := os.storeFeeHistory(estimate.blocks); err != nil {
+				log.Error("Oracle synchronizer store gas fee history fail", "err", err)
+			}
 		}
-		return nil
 	})
 	return nil
 }
 
-func (os *OracleSynchronizer) processTokenPrice(chainId uint64) (*big.Int, error) {
+// feeEstimate is the outcome of sampling the latest blocks of a chain.
+type feeEstimate struct {
+	fee         *big.Int
+	baseFee     *big.Int
+	gasPrice    *big.Int
+	priorityFee *big.Int
+	blocks      []database.GasFeeHistory
+}
+
+func (os *OracleSynchronizer) processTokenPrice(chainId uint64) (*feeEstimate, error) {
 	var gasPrice *big.Int
 	var transactionFee *big.Int
 	var blockFee = big.NewInt(0)
 	var fee = big.NewInt(0)
+	var gasPriceSum = big.NewInt(0)
+	var priorityFeeSum = big.NewInt(0)
+	var txCount int64
+	var latestBaseFee *big.Int
+	var blocks []database.GasFeeHistory
 	log.Info("process token price", "chainId", chainId)
 	latestBlockN, err := os.ethClient.GetLatestBlock(context.Background())
 	if err != nil {
@@ -103,17 +138,32 @@ func (os *OracleSynchronizer) processTokenPrice(chainId uint64) (*big.Int, error
 	log.Info("start handle block fee", "blockOffset", os.blockOffset, "latestBlockN", latestBlockN.String(), "chainId", chainId)
 	for i := 0; i < int(os.blockOffset); i++ {
 		blockNumber := int(latestBlockN.Int64()) - i
-		txs, baseFee, err := os.ethClient.BlockDetailByNumber(context.Background(), big.NewInt(int64(blockNumber)))
+		block, err := os.ethClient.BlockFeeDetailByNumber(context.Background(), big.NewInt(int64(blockNumber)))
 		if err != nil {
 			log.Error("failed to get block", "blockNum", blockNumber, "err", err)
 			return nil, err
 		}
+		txs, baseFee := block.Transactions, block.BaseFee
+		if latestBaseFee == nil {
+			latestBaseFee = baseFee
+		}
 		log.Info("successfully get block info", "block_num", blockNumber, "tx_len", len(txs))
 
+		history := database.GasFeeHistory{
+			ChainId:      new(big.Int).SetUint64(chainId),
+			BlockNumber:  block.Number,
+			BaseFee:      baseFee,
+			GasUsedRatio: gasUsedRatio(block.GasUsed, block.GasLimit),
+			Timestamp:    uint64(time.Now().Unix()),
+		}
+
 		if len(txs) <= 0 {
+			setPriorityFeePercentiles(&history, nil)
+			blocks = append(blocks, history)
 			continue
 		}
 
+		priorityFees := make([]*big.Int, 0, len(txs))
 		for _, tx := range txs {
 			receipt, err := os.ethClient.TxReceiptDetailByHash(context.Background(), common.HexToHash(tx))
 			if err != nil {
@@ -129,13 +179,52 @@ func (os *OracleSynchronizer) processTokenPrice(chainId uint64) (*big.Int, error
 				transactionFee = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed))
 			}
 			blockFee = new(big.Int).Add(blockFee, transactionFee)
+
+			priorityFee := new(big.Int).Sub(gasPrice, baseFee)
+			if priorityFee.Sign() < 0 {
+				priorityFee.SetInt64(0)
+			}
+			priorityFees = append(priorityFees, priorityFee)
+			gasPriceSum.Add(gasPriceSum, gasPrice)
+			priorityFeeSum.Add(priorityFeeSum, priorityFee)
+			txCount++
 		}
+		setPriorityFeePercentiles(&history, priorityFees)
+		blocks = append(blocks, history)
 
 		log.Info("block fee", "blockFee", blockFee, "txLen", len(txs))
 
 		blockFee = new(big.Int).Div(blockFee, big.NewInt(int64(len(txs))))
 		fee = new(big.Int).Add(fee, new(big.Int).Div(blockFee, big.NewInt(int64(os.blockOffset))))
 	}
-	log.Info("successfully get estimated fee", "chainId", chainId, "fee", fee)
-	return fee, nil
+
+	estimate := &feeEstimate{
+		fee:         fee,
+		baseFee:     latestBaseFee,
+		gasPrice:    latestBaseFee,
+		priorityFee: big.NewInt(0),
+		blocks:      blocks,
+	}
+	if txCount > 0 {
+		estimate.gasPrice = new(big.Int).Div(gasPriceSum, big.NewInt(txCount))
+		estimate.priorityFee = new(big.Int).Div(priorityFeeSum, big.NewInt(txCount))
+	}
+	log.Info("successfully get estimated fee", "chainId", chainId, "fee", fee, "gasPrice", estimate.gasPrice, "priorityFee", estimate.priorityFee)
+	return estimate, nil
+}
+
+// storeFeeHistory records the sampled blocks and drops the ones that fell out
+// of the fee history window.
+func (os *OracleSynchronizer) storeFeeHistory(blocks []database.GasFeeHistory) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	if err := os.db.GasFeeHistory.StoreGasFeeHistories(blocks); err != nil {
+		return err
+	}
+	oldest := new(big.Int).Sub(blocks[0].BlockNumber, big.NewInt(FeeHistoryWindow))
+	if oldest.Sign() <= 0 {
+		return nil
+	}
+	return os.db.GasFeeHistory.DeleteGasFeeHistoriesBefore(strconv.FormatUint(os.chainId, 10), oldest)
 }
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cpchain-network/gas-oracle/synchronizer/retry"
+)
+
+const maxSymbolBackoff = 5 * time.Minute
+
+// symbolBackoff skips the skyeye requests of a price that keeps failing for a
+// growing number of rounds, so one broken symbol does not slow the others.
+type symbolBackoff struct {
+	strategy retry.Strategy
+
+	mu       sync.Mutex
+	failures map[string]int
+	until    map[string]time.Time
+}
+
+func newSymbolBackoff() *symbolBackoff {
+	return &symbolBackoff{
+		strategy: &retry.ExponentialStrategy{Min: 0, Max: maxSymbolBackoff, MaxJitter: time.Second},
+		failures: make(map[string]int),
+		until:    make(map[string]time.Time),
+	}
+}
+
+// ready reports whether key may be requested at now.
+func (b *symbolBackoff) ready(key string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.until[key])
+}
+
+// failed backs key off for longer with each consecutive failure and returns
+// how long.
+func (b *symbolBackoff) failed(key string, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wait := b.strategy.Duration(b.failures[key])
+	b.failures[key]++
+	b.until[key] = now.Add(wait)
+	return wait
+}
+
+func (b *symbolBackoff) succeeded(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, key)
+	delete(b.until, key)
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+type PriceGuard struct {
+	// MaxDeviation is the largest move in percent accepted against the last
+	// stored price, zero only rejects non-positive prices
+	MaxDeviation float64
+	// Window only compares against stored prices younger than it, any age when zero
+	Window time.Duration
+	// Confirmations is how many consecutive agreeing samples accept a larger move
+	Confirmations int
+}
+
+// priceBreaker guards each symbol and quote currency against bad samples.
+// Samples of a large move are held back until enough consecutive ones agree.
+type priceBreaker struct {
+	guard PriceGuard
+
+	mu      sync.Mutex
+	pending map[string][]*big.Rat
+}
+
+func newPriceBreaker(guard PriceGuard) *priceBreaker {
+	return &priceBreaker{guard: guard, pending: make(map[string][]*big.Rat)}
+}
+
+// check decides whether price may replace last, the stored price of the pair
+// or nil, and keeps the held back samples right away. A rejected price comes
+// with the reason to freeze the pair.
+func (b *priceBreaker) check(key string, price *big.Rat, last *database.TokenPrice, now time.Time) (bool, string) {
+	round := b.begin()
+	ok, reason := round.check(key, price, last, now)
+	round.commit()
+	return ok, reason
+}
+
+// begin starts a round of checks whose held back samples only count once the
+// round is committed, so a round whose prices fail to store leaves no trace.
+func (b *priceBreaker) begin() *breakerRound {
+	return &breakerRound{breaker: b, pending: make(map[string][]*big.Rat)}
+}
+
+// breakerRound stages the held back samples of one pricing round. An empty
+// staged entry clears the pair.
+type breakerRound struct {
+	breaker *priceBreaker
+	pending map[string][]*big.Rat
+}
+
+// check is priceBreaker.check with the held back samples staged in the round.
+func (r *breakerRound) check(key string, price *big.Rat, last *database.TokenPrice, now time.Time) (bool, string) {
+	if price == nil || price.Sign() <= 0 {
+		return false, fmt.Sprintf("non-positive price %v", price)
+	}
+
+	guard := r.breaker.guard
+	if guard.MaxDeviation == 0 || last == nil || last.MarketPrice == nil || last.MarketPrice.Sign() <= 0 ||
+		(guard.Window > 0 && now.Sub(time.Unix(int64(last.Timestamp), 0)) > guard.Window) {
+		r.pending[key] = nil
+		return true, ""
+	}
+	deviation := deviationPercent(last.MarketPrice, price)
+	if deviation <= guard.MaxDeviation {
+		r.pending[key] = nil
+		return true, ""
+	}
+
+	// a sample disagreeing with the held back ones starts a new move
+	pending := slices.Clone(r.held(key))
+	if len(pending) > 0 && deviationPercent(pending[0], price) > guard.MaxDeviation {
+		pending = nil
+	}
+	pending = append(pending, price)
+	if len(pending) >= guard.Confirmations {
+		r.pending[key] = nil
+		return true, ""
+	}
+	r.pending[key] = pending
+	return false, fmt.Sprintf("moved %.2f%% from %s, %d/%d confirmations", deviation, last.MarketPriceString(), len(pending), guard.Confirmations)
+}
+
+// held returns the samples held back for key as of this round.
+func (r *breakerRound) held(key string) []*big.Rat {
+	if pending, ok := r.pending[key]; ok {
+		return pending
+	}
+	r.breaker.mu.Lock()
+	defer r.breaker.mu.Unlock()
+	return r.breaker.pending[key]
+}
+
+// commit applies the staged samples to the breaker.
+func (r *breakerRound) commit() {
+	r.breaker.mu.Lock()
+	defer r.breaker.mu.Unlock()
+	for key, pending := range r.pending {
+		if len(pending) == 0 {
+			delete(r.breaker.pending, key)
+		} else {
+			r.breaker.pending[key] = pending
+		}
+	}
+}
+
+// deviationPercent is |price - reference| / reference in percent.
+func deviationPercent(reference *big.Rat, price *big.Rat) float64 {
+	delta := new(big.Rat).Sub(price, reference)
+	delta.Abs(delta).Quo(delta, reference)
+	percent, _ := delta.Float64()
+	return percent * 100
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/database"
+)
+
+func TestPriceBreakerRejectsNonPositive(t *testing.T) {
+	breaker := newPriceBreaker(PriceGuard{})
+	ok, reason := breaker.check("eth/USD", big.NewRat(0, 1), nil, time.Now())
+	require.False(t, ok)
+	require.Contains(t, reason, "non-positive")
+	ok, _ = breaker.check("eth/USD", big.NewRat(-1, 1), nil, time.Now())
+	require.False(t, ok)
+	ok, _ = breaker.check("eth/USD", big.NewRat(1, 1), nil, time.Now())
+	require.True(t, ok)
+}
+
+func TestPriceBreakerDeviation(t *testing.T) {
+	now := time.Now()
+	breaker := newPriceBreaker(PriceGuard{MaxDeviation: 10, Window: time.Minute, Confirmations: 3})
+	last := &database.TokenPrice{MarketPrice: big.NewRat(100, 1), Timestamp: uint64(now.Unix())}
+
+	ok, _ := breaker.check("eth/USD", big.NewRat(109, 1), last, now)
+	require.True(t, ok, "moves within max_deviation pass")
+
+	ok, reason := breaker.check("eth/USD", big.NewRat(150, 1), last, now)
+	require.False(t, ok)
+	require.Equal(t, "moved 50.00% from 100, 1/3 confirmations", reason)
+	// a sample disagreeing with the held back move starts over
+	ok, reason = breaker.check("eth/USD", big.NewRat(50, 1), last, now)
+	require.False(t, ok)
+	require.Contains(t, reason, "1/3 confirmations")
+	ok, _ = breaker.check("eth/USD", big.NewRat(52, 1), last, now)
+	require.False(t, ok)
+	ok, _ = breaker.check("eth/USD", big.NewRat(51, 1), last, now)
+	require.True(t, ok, "the third agreeing sample confirms the move")
+
+	// stored prices older than the window are not compared against
+	stale := &database.TokenPrice{MarketPrice: big.NewRat(100, 1), Timestamp: uint64(now.Add(-2 * time.Minute).Unix())}
+	ok, _ = breaker.check("btc/USD", big.NewRat(300, 1), stale, now)
+	require.True(t, ok)
+}
+
+func TestStoreTokenPriceFreezes(t *testing.T) {
+	db := database.NewMemoryDB()
+	sh := &WorkerHandle{
+		db:          db,
+		wConf:       &WorkerHandleConfig{},
+		resourceCtx: context.Background(),
+		breaker:     newPriceBreaker(PriceGuard{MaxDeviation: 10, Confirmations: 2}),
+	}
+	symbol := Symbols{Name: "eth", Decimal: 18}
+	store := func(price int64) *database.TokenPrice {
+		round := sh.breaker.begin()
+		_, err := sh.storeTokenPrice(db, round, symbol, "USD", "eth", &derivedPrice{price: big.NewRat(price, 1), confidence: 1})
+		require.NoError(t, err)
+		round.commit()
+		tokenPrice, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+		require.NoError(t, err)
+		return tokenPrice
+	}
+
+	tokenPrice := store(3000)
+	require.Equal(t, database.PriceStateOk, tokenPrice.PriceState)
+
+	tokenPrice = store(30)
+	require.Equal(t, database.PriceStateFrozen, tokenPrice.PriceState)
+	require.Equal(t, "3000", tokenPrice.MarketPriceString(), "the last good price is kept")
+	require.Contains(t, tokenPrice.PriceStateReason, "1/2 confirmations")
+
+	tokenPrice = store(31)
+	require.Equal(t, database.PriceStateOk, tokenPrice.PriceState)
+	require.Equal(t, "31", tokenPrice.MarketPriceString())
+}
+
+func TestBreakerRoundOnlyCountsOnCommit(t *testing.T) {
+	breaker := newPriceBreaker(PriceGuard{MaxDeviation: 10, Confirmations: 2})
+	now := time.Now()
+	last := &database.TokenPrice{MarketPrice: big.NewRat(100, 1), Timestamp: uint64(now.Unix())}
+
+	// a round whose prices fail to store is dropped without commit
+	rolledBack := breaker.begin()
+	ok, _ := rolledBack.check("eth/USD", big.NewRat(50, 1), last, now)
+	require.False(t, ok)
+	require.Empty(t, breaker.pending)
+
+	round := breaker.begin()
+	ok, reason := round.check("eth/USD", big.NewRat(50, 1), last, now)
+	require.False(t, ok)
+	require.Contains(t, reason, "1/2 confirmations", "the rolled back sample does not count")
+	round.commit()
+
+	round = breaker.begin()
+	ok, _ = round.check("eth/USD", big.NewRat(51, 1), last, now)
+	require.True(t, ok)
+	require.Len(t, breaker.pending, 1, "the confirmation is staged until commit")
+	round.commit()
+	require.Empty(t, breaker.pending)
+}
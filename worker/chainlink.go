@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cpchain-network/gas-oracle/synchronizer/node"
+)
+
+// DefaultChainlinkMaxAge is the freshness bound of a feed without MaxAge.
+const DefaultChainlinkMaxAge = time.Hour
+
+// aggregatorV3ABI holds the AggregatorV3Interface views the worker reads.
+const aggregatorV3ABI = `[
+	{"type":"function","name":"latestRoundData","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},
+		{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+var aggregatorABI = mustParseABI(aggregatorV3ABI)
+
+type ChainlinkFeed struct {
+	ChainId uint64
+	Address common.Address
+	Base    string
+	Quote   string
+	// MaxAge rejects rounds updated longer ago, DefaultChainlinkMaxAge when zero
+	MaxAge time.Duration
+	// Primary prefers the feed over skyeye, otherwise it is only a fallback
+	Primary bool
+}
+
+// chainlinkSource labels the rates of a feed, like chainlink:0x5f4e...
+func chainlinkSource(feed ChainlinkFeed) string {
+	return "chainlink:" + feed.Address.Hex()
+}
+
+// chainlinkRate reads what one base is worth in quote from the latest round
+// of the feed, rejecting incomplete and stale rounds.
+func (sh *WorkerHandle) chainlinkRate(feed ChainlinkFeed) (*big.Rat, error) {
+	client, err := sh.chainClient(feed.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(sh.resourceCtx, chainCallTimeout)
+	defer cancel()
+
+	decimals, err := sh.feedDecimals(ctx, client, feed)
+	if err != nil {
+		return nil, err
+	}
+	out, err := callContract(ctx, client, aggregatorABI, feed.Address, "latestRoundData")
+	if err != nil {
+		return nil, err
+	}
+	roundId, answer, updatedAt, answeredInRound := out[0].(*big.Int), out[1].(*big.Int), out[3].(*big.Int), out[4].(*big.Int)
+	if updatedAt.Sign() == 0 {
+		return nil, fmt.Errorf("round %s is not complete", roundId)
+	}
+	if answeredInRound.Cmp(roundId) < 0 {
+		return nil, fmt.Errorf("round %s was answered in the earlier round %s", roundId, answeredInRound)
+	}
+	maxAge := feed.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultChainlinkMaxAge
+	}
+	if age := time.Since(time.Unix(updatedAt.Int64(), 0)); age > maxAge {
+		return nil, fmt.Errorf("round %s is %s old, max age is %s", roundId, age.Truncate(time.Second), maxAge)
+	}
+	if answer.Sign() <= 0 {
+		return nil, fmt.Errorf("round %s answered %s", roundId, answer)
+	}
+	return scaleDecimals(new(big.Rat).SetInt(answer), 0, decimals), nil
+}
+
+// feedDecimals reads the decimals of the feed answer once per feed.
+func (sh *WorkerHandle) feedDecimals(ctx context.Context, client node.EthClient, feed ChainlinkFeed) (uint8, error) {
+	key := contractKey{chainId: feed.ChainId, address: feed.Address}
+	sh.decimalsMu.Lock()
+	decimals, ok := sh.feedDecimal[key]
+	sh.decimalsMu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	out, err := callContract(ctx, client, aggregatorABI, feed.Address, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals = out[0].(uint8)
+
+	sh.decimalsMu.Lock()
+	defer sh.decimalsMu.Unlock()
+	if sh.feedDecimal == nil {
+		sh.feedDecimal = make(map[contractKey]uint8)
+	}
+	sh.feedDecimal[key] = decimals
+	return decimals, nil
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var ethUsdFeed = common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+
+func latestRound(roundId int64, answer int64, updatedAt time.Time, answeredInRound int64) []interface{} {
+	return []interface{}{big.NewInt(roundId), big.NewInt(answer), big.NewInt(updatedAt.Unix()), big.NewInt(updatedAt.Unix()), big.NewInt(answeredInRound)}
+}
+
+func TestChainlinkRate(t *testing.T) {
+	client := newFakePoolClient()
+	client.results[ethUsdFeed] = map[string][]interface{}{
+		"decimals":        {uint8(8)},
+		"latestRoundData": latestRound(10, 300012345678, time.Now().Add(-time.Minute), 10),
+	}
+	sh := newDexWorker(client)
+	feed := ChainlinkFeed{ChainId: 1, Address: ethUsdFeed, Base: "eth", Quote: "USD"}
+
+	rate, err := sh.chainlinkRate(feed)
+	require.NoError(t, err)
+	require.Equal(t, "3000.12345678", rate.FloatString(8))
+
+	client.results[ethUsdFeed]["latestRoundData"] = latestRound(10, 300012345678, time.Now().Add(-2*time.Hour), 10)
+	_, err = sh.chainlinkRate(feed)
+	require.ErrorContains(t, err, "max age is 1h0m0s")
+	feed.MaxAge = 3 * time.Hour
+	_, err = sh.chainlinkRate(feed)
+	require.NoError(t, err)
+
+	client.results[ethUsdFeed]["latestRoundData"] = latestRound(10, 300012345678, time.Now(), 9)
+	_, err = sh.chainlinkRate(feed)
+	require.ErrorContains(t, err, "answered in the earlier round 9")
+
+	client.results[ethUsdFeed]["latestRoundData"] = latestRound(10, 300012345678, time.Unix(0, 0), 10)
+	_, err = sh.chainlinkRate(feed)
+	require.ErrorContains(t, err, "not complete")
+
+	client.results[ethUsdFeed]["latestRoundData"] = latestRound(10, 0, time.Now(), 10)
+	_, err = sh.chainlinkRate(feed)
+	require.ErrorContains(t, err, "answered 0")
+}
+
+func TestChainlinkPrimaryAndFallback(t *testing.T) {
+	skyeye := big.NewRat(3000, 1)
+	chainlink := big.NewRat(3001, 1)
+
+	// a primary feed added before skyeye wins the tie
+	graph := newPriceGraph()
+	graph.addRate("eth", "USD", chainlink, "chainlink", chainlinkPrimaryConfidence)
+	graph.addRate("eth", "USD", skyeye, skyeyeSource, skyeyeConfidence)
+	derived, ok := graph.resolve("eth", "USD")
+	require.True(t, ok)
+	require.Equal(t, chainlink, derived.price)
+
+	// a fallback feed only prices what skyeye does not
+	graph = newPriceGraph()
+	graph.addRate("eth", "USD", skyeye, skyeyeSource, skyeyeConfidence)
+	graph.addRate("eth", "USD", chainlink, "chainlink", chainlinkFallbackConfidence)
+	derived, ok = graph.resolve("eth", "USD")
+	require.True(t, ok)
+	require.Equal(t, skyeye, derived.price)
+
+	graph = newPriceGraph()
+	graph.addRate("eth", "EUR", big.NewRat(2700, 1), skyeyeSource, skyeyeConfidence)
+	graph.addRate("USD", "EUR", big.NewRat(9, 10), skyeyeSource, skyeyeConfidence)
+	graph.addRate("eth", "USD", chainlink, "chainlink", chainlinkFallbackConfidence)
+	derived, ok = graph.resolve("eth", "USD")
+	require.True(t, ok)
+	require.Equal(t, chainlink, derived.price, "a direct fallback beats a derived skyeye price")
+}
@@ -0,0 +1,277 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/cpchain-network/gas-oracle/synchronizer/node"
+)
+
+const (
+	chainCallTimeout = 10 * time.Second
+
+	DexPoolV2 = "v2"
+	DexPoolV3 = "v3"
+)
+
+// uniswapPoolABI holds the views of Uniswap v2 pairs, v3 pools and their
+// ERC20 tokens the worker reads.
+const uniswapPoolABI = `[
+	{"type":"function","name":"getReserves","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"},{"name":"blockTimestampLast","type":"uint32"}]},
+	{"type":"function","name":"slot0","stateMutability":"view","inputs":[],"outputs":[
+		{"name":"sqrtPriceX96","type":"uint160"},{"name":"tick","type":"int24"},{"name":"observationIndex","type":"uint16"},
+		{"name":"observationCardinality","type":"uint16"},{"name":"observationCardinalityNext","type":"uint16"},
+		{"name":"feeProtocol","type":"uint8"},{"name":"unlocked","type":"bool"}]},
+	{"type":"function","name":"observe","stateMutability":"view","inputs":[{"name":"secondsAgos","type":"uint32[]"}],"outputs":[
+		{"name":"tickCumulatives","type":"int56[]"},{"name":"secondsPerLiquidityCumulativeX128s","type":"uint160[]"}]},
+	{"type":"function","name":"token0","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"token1","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+var poolABI = mustParseABI(uniswapPoolABI)
+
+// q192 is 2^192, the square of the Q64.96 scale of a v3 sqrt price
+var q192 = new(big.Int).Lsh(big.NewInt(1), 192)
+
+type DexPool struct {
+	ChainId uint64
+	Address common.Address
+	// Version is DexPoolV2 or DexPoolV3, v2 when empty
+	Version string
+	Token0  string
+	Token1  string
+	// TwapWindow averages a v3 price over the observations of the window,
+	// the current slot0 price is read when zero
+	TwapWindow time.Duration
+}
+
+type contractKey struct {
+	chainId uint64
+	address common.Address
+}
+
+// poolTokens are the token0 and token1 contracts of a pool with their decimals.
+type poolTokens struct {
+	addresses [2]common.Address
+	decimals  [2]uint8
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid abi: %v", err))
+	}
+	return parsed
+}
+
+// dexPoolRate reads what one token0 is worth in token1 from the pool.
+func (sh *WorkerHandle) dexPoolRate(pool DexPool) (*big.Rat, error) {
+	client, err := sh.chainClient(pool.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(sh.resourceCtx, chainCallTimeout)
+	defer cancel()
+
+	tokens, err := sh.dexPoolTokens(ctx, client, pool)
+	if err != nil {
+		return nil, err
+	}
+	var rate *big.Rat
+	switch pool.Version {
+	case DexPoolV3:
+		rate, err = v3PoolRate(ctx, client, pool)
+	default:
+		rate, err = v2PoolRate(ctx, client, pool.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scaleDecimals(rate, tokens.decimals[0], tokens.decimals[1]), nil
+}
+
+// dexPoolSymbols names the tokens of the pool: the configured names resolved
+// through the symbol map, or else the symbols holding the pool token addresses.
+func (sh *WorkerHandle) dexPoolSymbols(pool DexPool) (string, string, error) {
+	symbolMap := sh.symbolMap()
+	if pool.Token0 != "" && pool.Token1 != "" {
+		return symbolMap.Canonical(pool.Token0), symbolMap.Canonical(pool.Token1), nil
+	}
+	client, err := sh.chainClient(pool.ChainId)
+	if err != nil {
+		return "", "", err
+	}
+	ctx, cancel := context.WithTimeout(sh.resourceCtx, chainCallTimeout)
+	defer cancel()
+
+	tokens, err := sh.dexPoolTokens(ctx, client, pool)
+	if err != nil {
+		return "", "", err
+	}
+	var names [2]string
+	for i, address := range tokens.addresses {
+		symbol, ok := symbolMap.ResolveAddress(pool.ChainId, address)
+		if !ok {
+			return "", "", fmt.Errorf("no symbol has address %s on chain %d", address, pool.ChainId)
+		}
+		names[i] = symbol
+	}
+	return names[0], names[1], nil
+}
+
+// dexPoolTokens reads the tokens of the pool and their decimals once per pool.
+func (sh *WorkerHandle) dexPoolTokens(ctx context.Context, client node.EthClient, pool DexPool) (poolTokens, error) {
+	key := contractKey{chainId: pool.ChainId, address: pool.Address}
+	sh.decimalsMu.Lock()
+	tokens, ok := sh.poolTokens[key]
+	sh.decimalsMu.Unlock()
+	if ok {
+		return tokens, nil
+	}
+
+	for i, method := range []string{"token0", "token1"} {
+		out, err := callContract(ctx, client, poolABI, pool.Address, method)
+		if err != nil {
+			return tokens, err
+		}
+		token := out[0].(common.Address)
+		out, err = callContract(ctx, client, poolABI, token, "decimals")
+		if err != nil {
+			return tokens, fmt.Errorf("cannot get decimals of %s: %w", token, err)
+		}
+		tokens.addresses[i] = token
+		tokens.decimals[i] = out[0].(uint8)
+	}
+
+	sh.decimalsMu.Lock()
+	defer sh.decimalsMu.Unlock()
+	if sh.poolTokens == nil {
+		sh.poolTokens = make(map[contractKey]poolTokens)
+	}
+	sh.poolTokens[key] = tokens
+	return tokens, nil
+}
+
+// chainClient returns the rpc client of chainId.
+func (sh *WorkerHandle) chainClient(chainId uint64) (node.EthClient, error) {
+	if sh.wConf.ChainClient == nil {
+		return nil, errors.New("no chain clients")
+	}
+	client, ok := sh.wConf.ChainClient(chainId)
+	if !ok {
+		return nil, fmt.Errorf("no client for chain %d", chainId)
+	}
+	return client, nil
+}
+
+// callContract runs a view of contractABI with eth_call on the latest block.
+func callContract(ctx context.Context, client node.EthClient, contractABI abi.ABI, address common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot call %s of %s: %w", method, address, err)
+	}
+	values, err := contractABI.Unpack(method, out)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s result of %s: %w", method, address, err)
+	}
+	return values, nil
+}
+
+// v2PoolRate is reserve1 / reserve0 in raw token units.
+func v2PoolRate(ctx context.Context, client node.EthClient, address common.Address) (*big.Rat, error) {
+	out, err := callContract(ctx, client, poolABI, address, "getReserves")
+	if err != nil {
+		return nil, err
+	}
+	reserve0, reserve1 := out[0].(*big.Int), out[1].(*big.Int)
+	if reserve0.Sign() == 0 || reserve1.Sign() == 0 {
+		return nil, errors.New("pool has no liquidity")
+	}
+	return new(big.Rat).SetFrac(reserve1, reserve0), nil
+}
+
+// v3PoolRate is the slot0 price, or the time weighted average price over the
+// pool TwapWindow, in raw token units.
+func v3PoolRate(ctx context.Context, client node.EthClient, pool DexPool) (*big.Rat, error) {
+	if pool.TwapWindow <= 0 {
+		out, err := callContract(ctx, client, poolABI, pool.Address, "slot0")
+		if err != nil {
+			return nil, err
+		}
+		sqrtPriceX96 := out[0].(*big.Int)
+		if sqrtPriceX96.Sign() == 0 {
+			return nil, errors.New("pool is not initialized")
+		}
+		return new(big.Rat).SetFrac(new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96), q192), nil
+	}
+
+	window := uint32(pool.TwapWindow / time.Second)
+	out, err := callContract(ctx, client, poolABI, pool.Address, "observe", []uint32{window, 0})
+	if err != nil {
+		return nil, err
+	}
+	tickCumulatives := out[0].([]*big.Int)
+	if len(tickCumulatives) != 2 {
+		return nil, fmt.Errorf("observe returned %d ticks", len(tickCumulatives))
+	}
+	return tickPrice(meanTick(tickCumulatives[0], tickCumulatives[1], window)), nil
+}
+
+// meanTick rounds towards negative infinity like the Uniswap OracleLibrary.
+func meanTick(tickCumulativeStart *big.Int, tickCumulativeEnd *big.Int, window uint32) int64 {
+	delta := new(big.Int).Sub(tickCumulativeEnd, tickCumulativeStart)
+	// euclidean division floors for a positive divisor
+	return new(big.Int).Div(delta, big.NewInt(int64(window))).Int64()
+}
+
+// tickPrice is 1.0001^tick, computed with 256 bits of mantissa.
+func tickPrice(tick int64) *big.Rat {
+	const prec = 256
+	base := new(big.Float).SetPrec(prec).SetRat(big.NewRat(10001, 10000))
+	price := new(big.Float).SetPrec(prec).SetInt64(1)
+	exp := tick
+	if exp < 0 {
+		exp = -exp
+	}
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			price.Mul(price, base)
+		}
+		base.Mul(base, base)
+	}
+	rate, _ := price.Rat(nil)
+	if tick < 0 {
+		rate.Inv(rate)
+	}
+	return rate
+}
+
+// scaleDecimals turns a raw token1 per token0 rate into whole tokens.
+func scaleDecimals(rate *big.Rat, decimals0 uint8, decimals1 uint8) *big.Rat {
+	if decimals0 == decimals1 {
+		return rate
+	}
+	exp := int64(decimals0) - int64(decimals1)
+	if exp < 0 {
+		exp = -exp
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	if decimals0 > decimals1 {
+		return new(big.Rat).Mul(rate, scale)
+	}
+	return new(big.Rat).Quo(rate, scale)
+}
@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/common/symbols"
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/synchronizer/node"
+)
+
+var (
+	usdcAddress = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	wethAddress = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	poolAddress = common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+)
+
+// fakePoolClient answers eth_call from canned results per contract and method
+// of the pool and aggregator abis.
+type fakePoolClient struct {
+	node.EthClient
+	results map[common.Address]map[string][]interface{}
+	calls   int
+}
+
+func (c *fakePoolClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	method, err := poolABI.MethodById(msg.Data)
+	if err != nil {
+		if method, err = aggregatorABI.MethodById(msg.Data); err != nil {
+			return nil, err
+		}
+	}
+	values, ok := c.results[*msg.To][method.Name]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return method.Outputs.Pack(values...)
+}
+
+func newFakePoolClient() *fakePoolClient {
+	return &fakePoolClient{results: map[common.Address]map[string][]interface{}{
+		usdcAddress: {"decimals": {uint8(6)}},
+		wethAddress: {"decimals": {uint8(18)}},
+		poolAddress: {
+			"token0": {usdcAddress},
+			"token1": {wethAddress},
+		},
+	}}
+}
+
+func newDexWorker(client *fakePoolClient) *WorkerHandle {
+	return &WorkerHandle{
+		wConf: &WorkerHandleConfig{ChainClient: func(chainId uint64) (node.EthClient, bool) {
+			return client, chainId == 1
+		}},
+		resourceCtx: context.Background(),
+	}
+}
+
+func TestDexPoolRateV2(t *testing.T) {
+	client := newFakePoolClient()
+	// 2,000,000 USDC against 1,000 WETH
+	reserve0 := new(big.Int).Mul(big.NewInt(2_000_000), big.NewInt(1_000_000))
+	reserve1 := new(big.Int).Mul(big.NewInt(1_000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	client.results[poolAddress]["getReserves"] = []interface{}{reserve0, reserve1, uint32(1700000000)}
+	sh := newDexWorker(client)
+
+	pool := DexPool{ChainId: 1, Address: poolAddress, Token0: "USDC", Token1: "ETH"}
+	rate, err := sh.dexPoolRate(pool)
+	require.NoError(t, err)
+	require.Equal(t, "1/2000", rate.String())
+
+	// decimals are only read once per pool
+	calls := client.calls
+	_, err = sh.dexPoolRate(pool)
+	require.NoError(t, err)
+	require.Equal(t, 1, client.calls-calls)
+
+	client.results[poolAddress]["getReserves"] = []interface{}{big.NewInt(0), reserve1, uint32(1700000000)}
+	_, err = sh.dexPoolRate(pool)
+	require.ErrorContains(t, err, "no liquidity")
+
+	_, err = sh.dexPoolRate(DexPool{ChainId: 10, Address: poolAddress})
+	require.ErrorContains(t, err, "no client for chain 10")
+}
+
+func TestDexPoolRateV3(t *testing.T) {
+	client := newFakePoolClient()
+	// sqrt(1/2000 * 10^12) * 2^96, a spot price of 1 USDC = 1/2000 WETH
+	sqrtPriceX96, _ := new(big.Int).SetString("1771595571142957166518320255467520", 10)
+	client.results[poolAddress]["slot0"] = []interface{}{sqrtPriceX96, big.NewInt(200311), uint16(0), uint16(1), uint16(1), uint8(0), true}
+	sh := newDexWorker(client)
+
+	pool := DexPool{ChainId: 1, Address: poolAddress, Version: DexPoolV3, Token0: "USDC", Token1: "ETH"}
+	rate, err := sh.dexPoolRate(pool)
+	require.NoError(t, err)
+	spot, _ := rate.Float64()
+	require.InDelta(t, 0.0005, spot, 1e-9)
+
+	// the twap of a constant tick 200311 over 30 minutes
+	window := 30 * time.Minute
+	client.results[poolAddress]["observe"] = []interface{}{
+		[]*big.Int{big.NewInt(1_000_000), big.NewInt(1_000_000 + 200311*1800)},
+		[]*big.Int{big.NewInt(0), big.NewInt(0)},
+	}
+	pool.TwapWindow = window
+	rate, err = sh.dexPoolRate(pool)
+	require.NoError(t, err)
+	twap, _ := rate.Float64()
+	require.InEpsilon(t, 0.0005, twap, 1e-4)
+}
+
+func TestDexPoolSymbols(t *testing.T) {
+	client := newFakePoolClient()
+	sh := newDexWorker(client)
+	sh.wConf.SymbolMap = symbols.NewMap([]config.Symbols{
+		{Name: "usdc", Addresses: []config.TokenAddress{{ChainId: 1, Address: usdcAddress.Hex()}}},
+		{Name: "eth", Aliases: []string{"weth"}},
+	})
+
+	// named tokens go through the aliases
+	token0, token1, err := sh.dexPoolSymbols(DexPool{ChainId: 1, Address: poolAddress, Token0: "USDC", Token1: "WETH"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"usdc", "eth"}, []string{token0, token1})
+
+	// unnamed tokens are matched by address
+	pool := DexPool{ChainId: 1, Address: poolAddress}
+	_, _, err = sh.dexPoolSymbols(pool)
+	require.ErrorContains(t, err, "no symbol has address "+wethAddress.Hex()+" on chain 1")
+	sh.wConf.SymbolMap = symbols.NewMap([]config.Symbols{
+		{Name: "usdc", Addresses: []config.TokenAddress{{ChainId: 1, Address: usdcAddress.Hex()}}},
+		{Name: "eth", Addresses: []config.TokenAddress{{ChainId: 1, Address: wethAddress.Hex()}}},
+	})
+	token0, token1, err = sh.dexPoolSymbols(pool)
+	require.NoError(t, err)
+	require.Equal(t, []string{"usdc", "eth"}, []string{token0, token1})
+}
+
+func TestMeanTickRoundsDown(t *testing.T) {
+	require.Equal(t, int64(2), meanTick(big.NewInt(0), big.NewInt(5), 2))
+	require.Equal(t, int64(-3), meanTick(big.NewInt(0), big.NewInt(-5), 2))
+	require.Equal(t, "1/1", tickPrice(0).String())
+	price, _ := tickPrice(-1).Float64()
+	require.InDelta(t, 1/1.0001, price, 1e-12)
+}
@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cpchain-network/gas-oracle/common/bigint"
+	"github.com/cpchain-network/gas-oracle/synchronizer/retry"
+	"github.com/cpchain-network/gas-oracle/worker/skyeye"
+)
+
+// skyeyeBatchSize is the most symbols asked for in one batch request
+const skyeyeBatchSize = 50
+
+var errSymbolBackoff = errors.New("backing off after failures")
+
+type marketSample struct {
+	symbol Symbols
+	// skyeyeSymbol is what skyeye calls the symbol
+	skyeyeSymbol  string
+	quoteCurrency string
+	price         *big.Rat
+	tokenName     string
+}
+
+// fetchMarketPrices requests every symbol in every quote currency from skyeye,
+// FetchConcurrency requests at a time. Symbols are asked for in batches while
+// skyeye supports them, the samples of a failed batch are then requested one
+// by one. Failed requests leave their sample without a price.
+func (sh *WorkerHandle) fetchMarketPrices(symbolList []Symbols) []marketSample {
+	symbolMap := sh.symbolMap()
+	samples := make([]marketSample, 0, len(symbolList)*len(sh.wConf.QuoteCurrencies))
+	for _, symbol := range symbolList {
+		for _, quoteCurrency := range sh.wConf.QuoteCurrencies {
+			samples = append(samples, marketSample{symbol: symbol, skyeyeSymbol: symbolMap.SkyeyeSymbol(symbol.Name), quoteCurrency: quoteCurrency})
+		}
+	}
+
+	var ready []*marketSample
+	for i := range samples {
+		sample := &samples[i]
+		if !sh.backoff.ready(tokenPriceKey(sample.symbol.Name, sample.quoteCurrency), time.Now()) {
+			log.Debug("skip skyeye market price", "symbol", sample.symbol.Name, "quoteCurrency", sample.quoteCurrency, "err", errSymbolBackoff)
+			continue
+		}
+		ready = append(ready, sample)
+	}
+	if sh.client.BatchSupported() {
+		ready = sh.fetchMarketPriceBatches(ready)
+	}
+
+	var group errgroup.Group
+	group.SetLimit(sh.fetchConcurrency())
+	for _, sample := range ready {
+		group.Go(func() error {
+			marketPrice, tokenName, err := sh.fetchMarketPrice(sample)
+			if err != nil {
+				// a fallback source may still price the pair
+				log.Warn("get skyeye market price fail", "symbol", sample.symbol.Name, "quoteCurrency", sample.quoteCurrency, "err", err)
+				return nil
+			}
+			sample.price, sample.tokenName = marketPrice, tokenName
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return samples
+}
+
+// fetchMarketPriceBatches asks for samples in batches per quote currency and
+// returns the samples of the batches that failed.
+func (sh *WorkerHandle) fetchMarketPriceBatches(samples []*marketSample) []*marketSample {
+	var batches [][]*marketSample
+	byQuote := make(map[string][]*marketSample)
+	for _, sample := range samples {
+		batch := append(byQuote[sample.quoteCurrency], sample)
+		if len(batch) == skyeyeBatchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		byQuote[sample.quoteCurrency] = batch
+	}
+	for _, quoteCurrency := range sh.wConf.QuoteCurrencies {
+		if batch := byQuote[quoteCurrency]; len(batch) > 0 {
+			batches = append(batches, batch)
+		}
+	}
+
+	failed := make([][]*marketSample, len(batches))
+	var group errgroup.Group
+	group.SetLimit(sh.fetchConcurrency())
+	for i, batch := range batches {
+		group.Go(func() error {
+			if err := sh.fetchMarketPriceBatch(batch); err != nil {
+				if !errors.Is(err, skyeye.ErrBatchUnsupported) {
+					log.Warn("get skyeye market price batch fail, requesting one by one", "quoteCurrency", batch[0].quoteCurrency, "err", err)
+				}
+				failed[i] = batch
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	var unbatched []*marketSample
+	for _, batch := range failed {
+		unbatched = append(unbatched, batch...)
+	}
+	return unbatched
+}
+
+func (sh *WorkerHandle) fetchMarketPriceBatch(batch []*marketSample) error {
+	symbols := make([]string, len(batch))
+	for i, sample := range batch {
+		symbols[i] = sample.skyeyeSymbol
+	}
+	ctx, cancel := context.WithTimeout(sh.resourceCtx, sh.requestTimeout())
+	defer cancel()
+	messages, err := sh.client.Prices(ctx, symbols, batch[0].quoteCurrency)
+	if err != nil {
+		return err
+	}
+	for _, sample := range batch {
+		// a symbol missing from the batch is one skyeye does not price
+		message, ok := messages[sample.skyeyeSymbol]
+		if !ok {
+			continue
+		}
+		marketPrice, err := parseMarketPrice(message)
+		if err != nil {
+			log.Warn("invalid skyeye market price", "symbol", sample.symbol.Name, "quoteCurrency", sample.quoteCurrency, "err", err)
+			continue
+		}
+		sample.price, sample.tokenName = marketPrice, message.BaseAsset
+		sh.backoff.succeeded(tokenPriceKey(sample.symbol.Name, sample.quoteCurrency))
+	}
+	return nil
+}
+
+// fetchMarketPrice asks skyeye for the price of the pair with retries, backing
+// the pair off for later rounds when every attempt fails.
+func (sh *WorkerHandle) fetchMarketPrice(sample *marketSample) (*big.Rat, string, error) {
+	key := tokenPriceKey(sample.symbol.Name, sample.quoteCurrency)
+	marketPrice, tokenName, err := retry.Do2(sh.resourceCtx, skyeyeAttempts, sh.retryStrategy, func() (*big.Rat, string, error) {
+		return sh.processMarketPrice(sample)
+	})
+	if err != nil {
+		wait := sh.backoff.failed(key, time.Now())
+		return nil, "", fmt.Errorf("%w, backing off for %s", err, wait.Truncate(time.Millisecond))
+	}
+	sh.backoff.succeeded(key)
+	return marketPrice, tokenName, nil
+}
+
+// processMarketPrice fetches the skyeye price of the sample. A nil price
+// means skyeye has no such pair.
+func (sh *WorkerHandle) processMarketPrice(sample *marketSample) (*big.Rat, string, error) {
+	symbol, quoteCurrency := sample.symbol, sample.quoteCurrency
+	ctx, cancel := context.WithTimeout(sh.resourceCtx, sh.requestTimeout())
+	defer cancel()
+
+	message, err := sh.client.Price(ctx, sample.skyeyeSymbol, quoteCurrency)
+	var apiErr *skyeye.APIError
+	if errors.As(err, &apiErr) {
+		log.Debug("skyeye has no market price", "symbol", symbol.Name, "quoteCurrency", quoteCurrency, "code", apiErr.Code)
+		return nil, "", nil
+	} else if err != nil {
+		return nil, "", fmt.Errorf("cannot get %s market price: %w", symbol.Name, err)
+	}
+
+	log.Info("token marker price success", "symbol", symbol.Name, "quoteCurrency", quoteCurrency, "price", message.Price)
+	marketPrice, err := parseMarketPrice(message)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s market price: %w", symbol.Name, err)
+	}
+	return marketPrice, message.BaseAsset, nil
+}
+
+// parseMarketPrice keeps the digits of cheap tokens by parsing the shortest
+// decimal that round-trips the float.
+func parseMarketPrice(message *skyeye.Message) (*big.Rat, error) {
+	return bigint.ParseDecimal(strconv.FormatFloat(message.Price, 'f', -1, 64))
+}
+
+func (sh *WorkerHandle) fetchConcurrency() int {
+	if sh.wConf.FetchConcurrency <= 0 {
+		return DefaultFetchConcurrency
+	}
+	return sh.wConf.FetchConcurrency
+}
+
+func (sh *WorkerHandle) requestTimeout() time.Duration {
+	if sh.wConf.RequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+	return sh.wConf.RequestTimeout
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+	// maxDerivationHops bounds how many rates are multiplied into one price
+	maxDerivationHops = 3
+	// derivedHopConfidence is kept per hop beyond the first, each extra rate
+	// adds the error of an independent sample
+	derivedHopConfidence = 0.95
+
+	skyeyeConfidence  = 1.0
+	dexPoolConfidence = 0.9
+	// a primary feed ties with skyeye and is added first, a fallback one
+	// loses to a direct skyeye price but beats any derived one
+	chainlinkPrimaryConfidence  = 1.0
+	chainlinkFallbackConfidence = 0.98
+
+	skyeyeSource = "skyeye"
+)
+
+// dexPoolSource labels the rates of a pool, like uniswap-v3:0x88e6...
+func dexPoolSource(pool DexPool) string {
+	version := pool.Version
+	if version == "" {
+		version = DexPoolV2
+	}
+	return "uniswap-" + version + ":" + pool.Address.Hex()
+}
+
+type priceEdge struct {
+	to         string
+	rate       *big.Rat
+	label      string
+	confidence float64
+}
+
+// priceGraph holds the rates sampled in one worker round. Assets are matched
+// case-insensitively so that a symbol eth and a quote currency ETH meet.
+type priceGraph struct {
+	edges map[string][]priceEdge
+}
+
+type derivedPrice struct {
+	price      *big.Rat
+	path       []string
+	confidence float64
+}
+
+func newPriceGraph() *priceGraph {
+	return &priceGraph{edges: make(map[string][]priceEdge)}
+}
+
+// addRate records that one base is worth rate quote, and its inverse.
+func (g *priceGraph) addRate(base string, quote string, rate *big.Rat, source string, confidence float64) {
+	if rate == nil || rate.Sign() <= 0 {
+		return
+	}
+	label := base + "/" + quote + "@" + source
+	from, to := strings.ToLower(base), strings.ToLower(quote)
+	g.edges[from] = append(g.edges[from], priceEdge{to: to, rate: new(big.Rat).Set(rate), label: label, confidence: confidence})
+	g.edges[to] = append(g.edges[to], priceEdge{to: from, rate: new(big.Rat).Inv(rate), label: label, confidence: confidence})
+}
+
+// resolve prices base in quote along the path of highest confidence, taking
+// the first path found on ties.
+func (g *priceGraph) resolve(base string, quote string) (*derivedPrice, bool) {
+	from, to := strings.ToLower(base), strings.ToLower(quote)
+	var best *derivedPrice
+	visited := map[string]bool{from: true}
+	var path []priceEdge
+
+	var walk func(node string)
+	walk = func(node string) {
+		if node == to {
+			candidate := g.derive(path)
+			if best == nil || candidate.confidence > best.confidence {
+				best = candidate
+			}
+			return
+		}
+		if len(path) == maxDerivationHops {
+			return
+		}
+		for _, edge := range g.edges[node] {
+			if visited[edge.to] {
+				continue
+			}
+			visited[edge.to] = true
+			path = append(path, edge)
+			walk(edge.to)
+			path = path[:len(path)-1]
+			visited[edge.to] = false
+		}
+	}
+	walk(from)
+	return best, best != nil
+}
+
+func (g *priceGraph) derive(path []priceEdge) *derivedPrice {
+	derived := &derivedPrice{price: big.NewRat(1, 1), confidence: 1}
+	for i, edge := range path {
+		derived.price.Mul(derived.price, edge.rate)
+		derived.path = append(derived.path, edge.label)
+		derived.confidence *= edge.confidence
+		if i > 0 {
+			derived.confidence *= derivedHopConfidence
+		}
+	}
+	return derived
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceGraphDirectRate(t *testing.T) {
+	graph := newPriceGraph()
+	graph.addRate("eth", "USD", big.NewRat(3000, 1), skyeyeSource, skyeyeConfidence)
+
+	derived, ok := graph.resolve("eth", "USD")
+	require.True(t, ok)
+	require.Equal(t, "3000/1", derived.price.String())
+	require.Equal(t, []string{"eth/USD@skyeye"}, derived.path)
+	require.Equal(t, 1.0, derived.confidence)
+
+	// the inverse rate is derived from the same sample
+	derived, ok = graph.resolve("USD", "ETH")
+	require.True(t, ok)
+	require.Equal(t, "1/3000", derived.price.String())
+
+	_, ok = graph.resolve("cp", "USD")
+	require.False(t, ok)
+}
+
+func TestPriceGraphTriangulates(t *testing.T) {
+	graph := newPriceGraph()
+	graph.addRate("cp", "ETH", big.NewRat(1, 1000), skyeyeSource, skyeyeConfidence)
+	graph.addRate("eth", "USD", big.NewRat(3000, 1), skyeyeSource, skyeyeConfidence)
+	graph.addRate("eth", "EUR", big.NewRat(2700, 1), skyeyeSource, skyeyeConfidence)
+
+	derived, ok := graph.resolve("cp", "USD")
+	require.True(t, ok)
+	require.Equal(t, "3/1", derived.price.String())
+	require.Equal(t, []string{"cp/ETH@skyeye", "eth/USD@skyeye"}, derived.path)
+	require.InDelta(t, derivedHopConfidence, derived.confidence, 1e-9)
+
+	// a direct rate beats a derived one
+	graph.addRate("cp", "USD", big.NewRat(31, 10), skyeyeSource, skyeyeConfidence)
+	derived, ok = graph.resolve("cp", "USD")
+	require.True(t, ok)
+	require.Equal(t, "31/10", derived.price.String())
+
+	// paths longer than maxDerivationHops are not taken
+	graph = newPriceGraph()
+	graph.addRate("a", "b", big.NewRat(1, 1), skyeyeSource, skyeyeConfidence)
+	graph.addRate("b", "c", big.NewRat(1, 1), skyeyeSource, skyeyeConfidence)
+	graph.addRate("c", "d", big.NewRat(1, 1), skyeyeSource, skyeyeConfidence)
+	graph.addRate("d", "e", big.NewRat(1, 1), skyeyeSource, skyeyeConfidence)
+	_, ok = graph.resolve("a", "d")
+	require.True(t, ok)
+	_, ok = graph.resolve("a", "e")
+	require.False(t, ok)
+}
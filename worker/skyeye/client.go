@@ -0,0 +1,138 @@
+package skyeye
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gresty "github.com/go-resty/resty/v2"
+)
+
+const (
+	pricePath  = "api/v1/ccxt/price"
+	pricesPath = "api/v1/ccxt/prices"
+
+	// BatchReprobeInterval is how long a missing batch endpoint is trusted
+	// before Prices tries it again, so a skyeye upgraded later is picked up
+	BatchReprobeInterval = 10 * time.Minute
+)
+
+var (
+	ErrHTTP            = errors.New("skyeye http error")
+	ErrInvalidResponse = errors.New("invalid skyeye response")
+	// ErrBatchUnsupported is returned by Prices for BatchReprobeInterval after
+	// skyeye answered the batch endpoint with 404 or 405
+	ErrBatchUnsupported = errors.New("skyeye has no batch price endpoint")
+)
+
+// APIError is a well-formed response with ok false, which skyeye answers for
+// pairs it does not price.
+type APIError struct {
+	Code int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("skyeye answered not ok with code %d", e.Code)
+}
+
+// Client queries the skyeye market price api.
+type Client struct {
+	client *gresty.Client
+	// batchUnsupportedAt is the unix nano time the batch endpoint was last
+	// found missing, zero while it is supported
+	batchUnsupportedAt atomic.Int64
+}
+
+func NewClient(baseUrl string) *Client {
+	client := gresty.New()
+	client.SetBaseURL(baseUrl)
+	return &Client{client: client}
+}
+
+// BatchSupported is false for BatchReprobeInterval after the batch endpoint
+// turned out to be missing.
+func (c *Client) BatchSupported() bool {
+	unsupportedAt := c.batchUnsupportedAt.Load()
+	return unsupportedAt == 0 || time.Since(time.Unix(0, unsupportedAt)) >= BatchReprobeInterval
+}
+
+// Price returns the price of symbol in quoteCurrency.
+func (c *Client) Price(ctx context.Context, symbol string, quoteCurrency string) (*Message, error) {
+	resultData, err := c.get(ctx, pricePath, map[string]string{"symbol": symbol, "quote": quoteCurrency})
+	if err != nil {
+		return nil, err
+	}
+	var message Message
+	if err := json.Unmarshal(resultData.Result, &message); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := message.validate(quoteCurrency); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Prices returns the prices of symbols in quoteCurrency in one request, keyed
+// by the requested symbol. Symbols skyeye does not price, or prices invalidly,
+// are left out.
+func (c *Client) Prices(ctx context.Context, symbols []string, quoteCurrency string) (map[string]*Message, error) {
+	if !c.BatchSupported() {
+		return nil, ErrBatchUnsupported
+	}
+	resultData, err := c.get(ctx, pricesPath, map[string]string{"symbols": strings.Join(symbols, ","), "quote": quoteCurrency})
+	if err != nil {
+		return nil, err
+	}
+	var messages []Message
+	if err := json.Unmarshal(resultData.Result, &messages); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	prices := make(map[string]*Message, len(symbols))
+	for i := range messages {
+		message := &messages[i]
+		if message.validate(quoteCurrency) != nil {
+			continue
+		}
+		for _, symbol := range symbols {
+			if strings.EqualFold(message.BaseAsset, symbol) {
+				prices[symbol] = message
+			}
+		}
+	}
+	return prices, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, params map[string]string) (*ResultData, error) {
+	response, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		Get(path)
+	if err != nil {
+		return nil, err
+	}
+	statusCode := response.StatusCode()
+	if path == pricesPath && (statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed) {
+		c.batchUnsupportedAt.Store(time.Now().UnixNano())
+		return nil, ErrBatchUnsupported
+	}
+	if path == pricesPath {
+		c.batchUnsupportedAt.Store(0)
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("%d cannot %s %s: %w", statusCode, response.Request.Method, response.Request.URL, ErrHTTP)
+	}
+
+	var resultData ResultData
+	if err := json.Unmarshal(response.Body(), &resultData); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := resultData.validate(); err != nil {
+		return nil, err
+	}
+	return &resultData, nil
+}
@@ -0,0 +1,66 @@
+package skyeye
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cpchain-network/gas-oracle/worker/skyeye/skyeyetest"
+)
+
+func TestClientPrice(t *testing.T) {
+	client := NewClient(skyeyetest.NewServer(t, "testdata").URL)
+	ctx := context.Background()
+
+	message, err := client.Price(ctx, "eth", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "ETH", message.BaseAsset)
+	require.Equal(t, 3012.45, message.Price)
+
+	_, err = client.Price(ctx, "cp", "USD")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr), "want *APIError, got %v", err)
+	require.Equal(t, 404, apiErr.Code)
+
+	_, err = client.Price(ctx, "eth", "EUR")
+	require.ErrorIs(t, err, ErrInvalidResponse)
+	require.ErrorContains(t, err, "quoted in USD")
+	_, err = client.Price(ctx, "btc", "USD")
+	require.ErrorIs(t, err, ErrInvalidResponse)
+	require.ErrorContains(t, err, "missing ok")
+	_, err = client.Price(ctx, "pepe", "USD")
+	require.ErrorContains(t, err, "non-positive")
+
+	_, err = client.Price(ctx, "doge", "USD")
+	require.ErrorIs(t, err, ErrHTTP)
+}
+
+func TestClientPrices(t *testing.T) {
+	client := NewClient(skyeyetest.NewServer(t, "testdata").URL)
+	ctx := context.Background()
+
+	prices, err := client.Prices(ctx, []string{"btc", "eth", "pepe"}, "USD")
+	require.NoError(t, err)
+	require.Len(t, prices, 2, "invalid entries are left out")
+	require.Equal(t, 67012.1, prices["btc"].Price)
+	require.Equal(t, 3012.45, prices["eth"].Price)
+	require.True(t, client.BatchSupported())
+
+	// a missing batch endpoint is remembered
+	_, err = client.Prices(ctx, []string{"cp"}, "USD")
+	require.ErrorIs(t, err, ErrBatchUnsupported)
+	require.False(t, client.BatchSupported())
+	_, err = client.Prices(ctx, []string{"btc", "eth", "pepe"}, "USD")
+	require.ErrorIs(t, err, ErrBatchUnsupported)
+
+	// and probed again once the interval passed
+	client.batchUnsupportedAt.Store(time.Now().Add(-BatchReprobeInterval).UnixNano())
+	require.True(t, client.BatchSupported())
+	prices, err = client.Prices(ctx, []string{"btc", "eth", "pepe"}, "USD")
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	require.True(t, client.BatchSupported())
+}
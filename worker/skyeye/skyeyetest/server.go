@@ -0,0 +1,97 @@
+// Package skyeyetest serves skyeye responses from fixture files through an
+// httptest server.
+//
+// Each request maps to one fixture file in the fixture directory:
+//
+//	/api/v1/ccxt/price?symbol=eth&quote=USD        -> price_eth_usd.json
+//	/api/v1/ccxt/prices?symbols=btc,eth&quote=USD  -> prices_btc-eth_usd.json
+//
+// A request without a fixture is answered 404. With SKYEYE_RECORD_URL set the
+// server proxies every request to that skyeye and records its 200 answers as
+// fixtures instead.
+//
+// Fixtures in the invalid subdirectory are hand-written answers skyeye should
+// never give, such as a price quoted in the wrong currency, to exercise the
+// validation of the client. They answer their requests in record mode too and
+// are never overwritten.
+package skyeyetest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// RecordEnv names the skyeye to record fixtures from.
+	RecordEnv = "SKYEYE_RECORD_URL"
+	// InvalidDir is the subdirectory of hand-written invalid fixtures.
+	InvalidDir = "invalid"
+)
+
+// NewServer serves the fixtures of dir until the test ends.
+func NewServer(t testing.TB, dir string) *httptest.Server {
+	recordUrl := os.Getenv(RecordEnv)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := os.ReadFile(filepath.Join(dir, InvalidDir, FixtureName(r)))
+		if err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(body)
+			return
+		}
+		fixture := filepath.Join(dir, FixtureName(r))
+		if recordUrl != "" {
+			record(t, w, r, recordUrl, fixture)
+			return
+		}
+		body, err = os.ReadFile(fixture)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// FixtureName is the fixture file answering r.
+func FixtureName(r *http.Request) string {
+	query := r.URL.Query()
+	endpoint := path.Base(r.URL.Path)
+	symbols := query.Get("symbol")
+	if endpoint == "prices" {
+		symbols = strings.ReplaceAll(query.Get("symbols"), ",", "-")
+	}
+	return strings.ToLower(endpoint + "_" + symbols + "_" + query.Get("quote") + ".json")
+}
+
+func record(t testing.TB, w http.ResponseWriter, r *http.Request, recordUrl string, fixture string) {
+	response, err := http.Get(strings.TrimSuffix(recordUrl, "/") + r.URL.RequestURI())
+	if err != nil {
+		t.Errorf("record %s: %v", r.URL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Errorf("record %s: %v", r.URL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if response.StatusCode == http.StatusOK {
+		if err := os.WriteFile(fixture, body, 0o644); err != nil {
+			t.Errorf("record %s: %v", r.URL, err)
+		}
+	}
+	w.Header().Set("Content-Type", response.Header.Get("Content-Type"))
+	w.WriteHeader(response.StatusCode)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,55 @@
+package skyeye
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResultData is the envelope of every skyeye response. Result holds a
+// Message for the price endpoint and a list of them for the batch one.
+type ResultData struct {
+	Ok     *bool           `json:"ok"`
+	Code   int             `json:"code"`
+	Result json.RawMessage `json:"result"`
+}
+
+type Message struct {
+	BaseAsset      string    `json:"base_asset"`
+	QuoteAsset     string    `json:"quote_asset"`
+	Exchange       string    `json:"exchange"`
+	Price          float64   `json:"price"`
+	PriceChange24H float64   `json:"price_change_24h"`
+	Volume24H      float64   `json:"volume_24h"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// validate checks the envelope, a response that is not ok is an *APIError.
+func (r *ResultData) validate() error {
+	if r.Ok == nil {
+		return fmt.Errorf("%w: missing ok", ErrInvalidResponse)
+	}
+	if !*r.Ok {
+		return &APIError{Code: r.Code}
+	}
+	if r.Code != 0 && (r.Code < 200 || r.Code >= 300) {
+		return fmt.Errorf("%w: ok with code %d", ErrInvalidResponse, r.Code)
+	}
+	if len(r.Result) == 0 || string(r.Result) == "null" {
+		return fmt.Errorf("%w: missing result", ErrInvalidResponse)
+	}
+	return nil
+}
+
+// validate checks a price of quoteCurrency.
+func (m *Message) validate(quoteCurrency string) error {
+	// never take a price quoted in another currency for this one
+	if m.QuoteAsset != "" && !strings.EqualFold(m.QuoteAsset, quoteCurrency) {
+		return fmt.Errorf("%w: %s quoted in %s, want %s", ErrInvalidResponse, m.BaseAsset, m.QuoteAsset, quoteCurrency)
+	}
+	if m.Price <= 0 {
+		return fmt.Errorf("%w: non-positive %s price %v", ErrInvalidResponse, m.BaseAsset, m.Price)
+	}
+	return nil
+}
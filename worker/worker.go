@@ -4,16 +4,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
-	gresty "github.com/go-resty/resty/v2"
+	"gorm.io/gorm"
 
+	"github.com/cpchain-network/gas-oracle/common/symbols"
 	"github.com/cpchain-network/gas-oracle/common/tasks"
 	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/event"
+	"github.com/cpchain-network/gas-oracle/synchronizer/node"
+	"github.com/cpchain-network/gas-oracle/synchronizer/retry"
+	"github.com/cpchain-network/gas-oracle/worker/skyeye"
 )
 
-var errMarketHTTPError = errors.New("Skyeye market price  http error")
+const (
+	// DefaultLoopInterval is the pause between pricing rounds when none is set
+	DefaultLoopInterval = 5 * time.Second
+	// DefaultFetchConcurrency bounds the parallel skyeye requests when none is set
+	DefaultFetchConcurrency = 8
+	// DefaultRequestTimeout bounds one skyeye request when none is set
+	DefaultRequestTimeout = 10 * time.Second
+
+	skyeyeAttempts = 3
+)
+
+// emptySymbolMap names every symbol as it is
+var emptySymbolMap = symbols.NewMap(nil)
+
+// skyeyeRetryStrategy spaces the attempts of one request within a round
+var skyeyeRetryStrategy = &retry.ExponentialStrategy{Min: 0, Max: 2 * time.Second, MaxJitter: 250 * time.Millisecond}
 
 type Symbols struct {
 	Name    string
@@ -24,37 +46,61 @@ type WorkerHandleConfig struct {
 	BaseUrl      string
 	LoopInterval time.Duration
 	SymbolList   []Symbols
+	// SymbolMap names each symbol at skyeye and resolves the tokens of
+	// DexPools and ChainlinkFeeds to symbols
+	SymbolMap *symbols.Map
+	// FetchConcurrency bounds the parallel skyeye requests of a round
+	FetchConcurrency int
+	// RequestTimeout bounds each skyeye request attempt
+	RequestTimeout time.Duration
+	// QuoteCurrencies are the currencies every symbol is priced in
+	QuoteCurrencies []string
+	// DexPools add on-chain rates for symbols skyeye does not price directly
+	DexPools []DexPool
+	// ChainlinkFeeds are read as primary or fallback sources next to skyeye
+	ChainlinkFeeds []ChainlinkFeed
+	// PriceGuard is the circuit breaker every stored price passes
+	PriceGuard PriceGuard
+	// ChainClient returns the rpc client of a chain to read DexPools and ChainlinkFeeds with
+	ChainClient func(chainId uint64) (node.EthClient, bool)
 }
 
 type WorkerHandle struct {
 	db             *database.DB
+	bus            *event.Bus
 	wConf          *WorkerHandleConfig
-	client         *gresty.Client
+	client         *skyeye.Client
 	resourceCtx    context.Context
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
+
+	symbolMu sync.RWMutex
+
+	// poolTokens caches the tokens of each dex pool, feedDecimal the answer
+	// decimals of each chainlink feed
+	decimalsMu  sync.Mutex
+	poolTokens  map[contractKey]poolTokens
+	feedDecimal map[contractKey]uint8
+
+	breaker       *priceBreaker
+	backoff       *symbolBackoff
+	retryStrategy retry.Strategy
 }
 
-func NewWorkerHandle(db *database.DB, wConf *WorkerHandleConfig, shutdown context.CancelCauseFunc) (*WorkerHandle, error) {
-	client := gresty.New()
-	client.SetBaseURL(wConf.BaseUrl)
-	client.OnAfterResponse(func(c *gresty.Client, r *gresty.Response) error {
-		statusCode := r.StatusCode()
-		if statusCode >= 400 {
-			method := r.Request.Method
-			url := r.Request.URL
-			return fmt.Errorf("%d cannot %s %s: %w", statusCode, method, url, errMarketHTTPError)
-		}
-		return nil
-	})
+func NewWorkerHandle(db *database.DB, bus *event.Bus, wConf *WorkerHandleConfig, shutdown context.CancelCauseFunc) (*WorkerHandle, error) {
+	client := skyeye.NewClient(wConf.BaseUrl)
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &WorkerHandle{
 		db:             db,
+		bus:            bus,
 		wConf:          wConf,
 		client:         client,
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
+		breaker:        newPriceBreaker(wConf.PriceGuard),
+		backoff:        newSymbolBackoff(),
+		retryStrategy:  skyeyeRetryStrategy,
 		tasks: tasks.Group{
 			HandleCrit: func(err error) {
 				shutdown(fmt.Errorf("critical error in worker handle processor: %w", err))
@@ -69,54 +115,204 @@ func (sh *WorkerHandle) Close() error {
 }
 
 func (sh *WorkerHandle) Start() error {
-	workerTicker := time.NewTicker(sh.wConf.LoopInterval)
+	loopInterval := sh.wConf.LoopInterval
+	if loopInterval <= 0 {
+		loopInterval = DefaultLoopInterval
+	}
+	workerTicker := time.NewTicker(loopInterval)
 	sh.tasks.Go(func() error {
-		for range workerTicker.C {
-			err := sh.onProcessMarkerPrice()
-			if err != nil {
+		defer workerTicker.Stop()
+		for {
+			select {
+			case <-sh.resourceCtx.Done():
+				log.Info("worker handle stopped")
+				return nil
+			case <-workerTicker.C:
+			}
+			// failures are per price, the next round retries them
+			if err := sh.onProcessMarkerPrice(); err != nil {
 				log.Error("process market price fail", "err", err)
-				return err
 			}
 		}
-		return nil
 	})
 	return nil
 }
 
+// SymbolList returns a copy of the symbols currently priced.
+func (sh *WorkerHandle) SymbolList() []Symbols {
+	sh.symbolMu.RLock()
+	defer sh.symbolMu.RUnlock()
+	return append([]Symbols(nil), sh.wConf.SymbolList...)
+}
+
+// SetSymbolList replaces the symbols priced and how they are named from the
+// next loop on.
+func (sh *WorkerHandle) SetSymbolList(symbolList []Symbols, symbolMap *symbols.Map) {
+	sh.symbolMu.Lock()
+	defer sh.symbolMu.Unlock()
+	sh.wConf.SymbolList = append([]Symbols(nil), symbolList...)
+	sh.wConf.SymbolMap = symbolMap
+}
+
+func (sh *WorkerHandle) symbolMap() *symbols.Map {
+	sh.symbolMu.RLock()
+	defer sh.symbolMu.RUnlock()
+	if sh.wConf.SymbolMap == nil {
+		return emptySymbolMap
+	}
+	return sh.wConf.SymbolMap
+}
+
+// onProcessMarkerPrice samples every direct rate of the round into a price
+// graph and prices each symbol in each quote currency from it, so a symbol
+// without a direct feed is derived through intermediate pairs. A failing
+// request only loses its own rate, while the prices of the round are stored
+// in one transaction and published once it commits.
 func (sh *WorkerHandle) onProcessMarkerPrice() error {
-	for _, symbol := range sh.wConf.SymbolList {
-		var resultData ResultData
-		response, err := sh.client.R().
-			SetQueryParam("symbol", symbol.Name).
-			SetResult(&resultData).
-			Get("api/v1/ccxt/price")
+	graph := newPriceGraph()
+	// primary feeds go first, the graph keeps the first path found on ties
+	sh.addChainlinkRates(graph, true)
+	tokenNames := make(map[string]string)
+	symbolList := sh.SymbolList()
+	for _, sample := range sh.fetchMarketPrices(symbolList) {
+		if sample.price != nil {
+			graph.addRate(sample.symbol.Name, sample.quoteCurrency, sample.price, skyeyeSource, skyeyeConfidence)
+			tokenNames[sample.symbol.Name] = sample.tokenName
+		}
+	}
+	for _, pool := range sh.wConf.DexPools {
+		rate, err := sh.dexPoolRate(pool)
 		if err != nil {
-			return fmt.Errorf("cannot get %s market price: %w", symbol.Name, err)
+			log.Warn("read dex pool rate fail", "chainId", pool.ChainId, "pool", pool.Address, "err", err)
+			continue
 		}
-		if response.StatusCode() != 200 {
-			return errors.New("get market price fail")
+		token0, token1, err := sh.dexPoolSymbols(pool)
+		if err != nil {
+			log.Warn("resolve dex pool tokens fail", "chainId", pool.ChainId, "pool", pool.Address, "err", err)
+			continue
 		}
+		graph.addRate(token0, token1, rate, dexPoolSource(pool), dexPoolConfidence)
+	}
+	sh.addChainlinkRates(graph, false)
 
-		log.Info("get token marker price success", "Ok", resultData.Ok, "code", resultData.Code)
+	var stored []*database.TokenPrice
+	var breaker *breakerRound
+	err := sh.db.Transaction(func(tx *database.DB) error {
+		stored = stored[:0]
+		breaker = sh.breaker.begin()
+		for _, symbol := range symbolList {
+			for _, quoteCurrency := range sh.wConf.QuoteCurrencies {
+				derived, ok := graph.resolve(symbol.Name, quoteCurrency)
+				if !ok {
+					log.Warn("no market price path", "symbol", symbol.Name, "quoteCurrency", quoteCurrency)
+					continue
+				}
+				tokenName, ok := tokenNames[symbol.Name]
+				if !ok {
+					tokenName = symbol.Name
+				}
+				tokenPrice, err := sh.storeTokenPrice(tx, breaker, symbol, quoteCurrency, tokenName, derived)
+				if err != nil {
+					return fmt.Errorf("cannot store %s price: %w", tokenPriceKey(symbol.Name, quoteCurrency), err)
+				}
+				if tokenPrice != nil {
+					stored = append(stored, tokenPrice)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// the held back samples only count once the prices checked against them are stored
+	breaker.commit()
+	for _, tokenPrice := range stored {
+		sh.bus.PublishTokenPrice(tokenPrice)
+	}
+	return nil
+}
 
-		if resultData.Ok {
-			returnPriceData := resultData.Result
+// storeTokenPrice stores the derived price unless the circuit breaker rejects
+// it, in which case the last good price is kept and marked frozen. It returns
+// the row to publish, if any.
+func (sh *WorkerHandle) storeTokenPrice(db *database.DB, breaker *breakerRound, symbol Symbols, quoteCurrency string, tokenName string, derived *derivedPrice) (*database.TokenPrice, error) {
+	last, err := db.TokenPrice.QueryTokenPrices(symbol.Name, quoteCurrency)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		last = nil
+	} else if err != nil {
+		return nil, err
+	}
 
-			log.Info("token marker price success", "symbol", symbol.Name, "code", returnPriceData.Price)
+	now := time.Now()
+	if ok, reason := breaker.check(tokenPriceKey(symbol.Name, quoteCurrency), derived.price, last, now); !ok {
+		return sh.freezeTokenPrice(db, symbol.Name, quoteCurrency, last, reason)
+	}
+	if last != nil && last.PriceState == database.PriceStateFrozen {
+		log.Info("price circuit breaker reset", "symbol", symbol.Name, "quoteCurrency", quoteCurrency)
+	}
 
-			tokenPrice := &database.TokenPrice{
-				TokenName:   returnPriceData.BaseAsset,
-				TokenSymbol: symbol.Name,
-				Decimal:     symbol.Decimal,
-				MarketPrice: fmt.Sprintf("%f", returnPriceData.Price),
-				Timestamp:   uint64(time.Now().Unix()),
-			}
-			err := sh.db.TokenPrice.StoreOrUpdateTokenPrice(tokenPrice)
-			if err != nil {
-				log.Error("Store or update token price fail", "err", err)
-				return err
-			}
+	tokenPrice := &database.TokenPrice{
+		TokenName:      tokenName,
+		TokenSymbol:    symbol.Name,
+		SkeyeSymbol:    sh.symbolMap().SkyeyeSymbol(symbol.Name),
+		QuoteCurrency:  quoteCurrency,
+		Decimal:        symbol.Decimal,
+		MarketPrice:    derived.price,
+		DerivationPath: strings.Join(derived.path, ","),
+		Confidence:     derived.confidence,
+		PriceState:     database.PriceStateOk,
+		Timestamp:      uint64(now.Unix()),
+	}
+	if err := db.TokenPrice.StoreOrUpdateTokenPrice(tokenPrice); err != nil {
+		log.Error("Store or update token price fail", "err", err)
+		return nil, err
+	}
+	return tokenPrice, nil
+}
+
+func (sh *WorkerHandle) freezeTokenPrice(db *database.DB, symbol string, quoteCurrency string, last *database.TokenPrice, reason string) (*database.TokenPrice, error) {
+	if last == nil {
+		log.Warn("rejected first market price", "symbol", symbol, "quoteCurrency", quoteCurrency, "reason", reason)
+		return nil, nil
+	}
+	if last.PriceState != database.PriceStateFrozen {
+		log.Error("price circuit breaker tripped, keeping last good price", "symbol", symbol, "quoteCurrency", quoteCurrency,
+			"price", last.MarketPriceString(), "reason", reason)
+	}
+	if last.PriceState == database.PriceStateFrozen && last.PriceStateReason == reason {
+		return nil, nil
+	}
+	if err := db.TokenPrice.UpdateTokenPriceState(symbol, quoteCurrency, database.PriceStateFrozen, reason); err != nil {
+		return nil, err
+	}
+	frozen := *last
+	frozen.PriceState = database.PriceStateFrozen
+	frozen.PriceStateReason = reason
+	return &frozen, nil
+}
+
+func tokenPriceKey(symbol string, quoteCurrency string) string {
+	return symbol + "/" + quoteCurrency
+}
+
+// addChainlinkRates adds the rates of the primary or of the fallback chainlink feeds,
+// skipping feeds without a fresh complete round.
+func (sh *WorkerHandle) addChainlinkRates(graph *priceGraph, primary bool) {
+	confidence := chainlinkFallbackConfidence
+	if primary {
+		confidence = chainlinkPrimaryConfidence
+	}
+	symbolMap := sh.symbolMap()
+	for _, feed := range sh.wConf.ChainlinkFeeds {
+		if feed.Primary != primary {
+			continue
 		}
+		rate, err := sh.chainlinkRate(feed)
+		if err != nil {
+			log.Warn("read chainlink feed fail", "chainId", feed.ChainId, "feed", feed.Address, "err", err)
+			continue
+		}
+		graph.addRate(symbolMap.Canonical(feed.Base), symbolMap.Canonical(feed.Quote), rate, chainlinkSource(feed), confidence)
 	}
-	return nil
 }
@@ -0,0 +1,208 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/cpchain-network/gas-oracle/common/symbols"
+	"github.com/cpchain-network/gas-oracle/config"
+	"github.com/cpchain-network/gas-oracle/database"
+	"github.com/cpchain-network/gas-oracle/synchronizer/retry"
+	"github.com/cpchain-network/gas-oracle/worker/skyeye/skyeyetest"
+)
+
+// fakeSkyeye prices every symbol at 3000 after delay, except that it fails
+// every request for broken and answers slow after a second. It has no batch
+// endpoint.
+type fakeSkyeye struct {
+	delay time.Duration
+
+	mu          sync.Mutex
+	requests    map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *fakeSkyeye) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v1/ccxt/price" {
+		http.NotFound(w, r)
+		return
+	}
+	symbol := r.URL.Query().Get("symbol")
+	s.mu.Lock()
+	s.requests[symbol]++
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	delay := s.delay
+	if symbol == "slow" {
+		delay = time.Second
+	}
+	select {
+	case <-time.After(delay):
+	case <-r.Context().Done():
+		return
+	}
+	if symbol == "broken" {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"ok":true,"code":200,"result":{"base_asset":"%s","quote_asset":"%s","price":3000}}`, symbol, r.URL.Query().Get("quote"))
+}
+
+func (s *fakeSkyeye) count(symbol string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[symbol]
+}
+
+func TestWorkerIsolatesFailingSymbols(t *testing.T) {
+	skyeye := &fakeSkyeye{requests: make(map[string]int)}
+	server := httptest.NewServer(skyeye)
+	defer server.Close()
+
+	db := database.NewMemoryDB()
+	sh, err := NewWorkerHandle(db, nil, &WorkerHandleConfig{
+		BaseUrl:         server.URL,
+		SymbolList:      []Symbols{{Name: "broken", Decimal: 18}, {Name: "eth", Decimal: 18}},
+		QuoteCurrencies: []string{"USD"},
+	}, func(cause error) { t.Errorf("worker shut down: %v", cause) })
+	require.NoError(t, err)
+	sh.retryStrategy = retry.Fixed(0)
+	defer sh.resourceCancel()
+
+	require.NoError(t, sh.onProcessMarkerPrice())
+	tokenPrice, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+	require.NoError(t, err, "a failing symbol does not stop the others")
+	require.Equal(t, "3000", tokenPrice.MarketPriceString())
+	_, err = db.TokenPrice.QueryTokenPrices("broken", "USD")
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	require.Equal(t, skyeyeAttempts, skyeye.count("broken"), "each round retries a failing request")
+
+	// the failing symbol is backed off while the others keep being priced
+	require.NoError(t, sh.onProcessMarkerPrice())
+	require.Equal(t, skyeyeAttempts, skyeye.count("broken"))
+	require.Equal(t, 2, skyeye.count("eth"))
+
+	sh.backoff.succeeded(tokenPriceKey("broken", "USD"))
+	require.NoError(t, sh.onProcessMarkerPrice())
+	require.Equal(t, 2*skyeyeAttempts, skyeye.count("broken"))
+}
+
+func TestSymbolBackoffGrows(t *testing.T) {
+	backoff := newSymbolBackoff()
+	backoff.strategy = &retry.ExponentialStrategy{Min: 0, Max: maxSymbolBackoff}
+	now := time.Now()
+	require.True(t, backoff.ready("eth/USD", now))
+
+	require.Equal(t, time.Second, backoff.failed("eth/USD", now))
+	require.False(t, backoff.ready("eth/USD", now))
+	require.True(t, backoff.ready("eth/USD", now.Add(time.Second)))
+	require.True(t, backoff.ready("btc/USD", now), "backoff is per price")
+
+	require.Equal(t, 2*time.Second, backoff.failed("eth/USD", now))
+	for i := 0; i < 20; i++ {
+		backoff.failed("eth/USD", now)
+	}
+	require.Equal(t, maxSymbolBackoff, backoff.failed("eth/USD", now))
+
+	backoff.succeeded("eth/USD")
+	require.True(t, backoff.ready("eth/USD", now))
+	require.Equal(t, time.Second, backoff.failed("eth/USD", now))
+}
+
+func TestWorkerFetchesConcurrently(t *testing.T) {
+	skyeye := &fakeSkyeye{delay: 20 * time.Millisecond, requests: make(map[string]int)}
+	server := httptest.NewServer(skyeye)
+	defer server.Close()
+
+	var symbolList []Symbols
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "slow"} {
+		symbolList = append(symbolList, Symbols{Name: name, Decimal: 18})
+	}
+	db := database.NewMemoryDB()
+	sh, err := NewWorkerHandle(db, nil, &WorkerHandleConfig{
+		BaseUrl:          server.URL,
+		SymbolList:       symbolList,
+		QuoteCurrencies:  []string{"USD"},
+		FetchConcurrency: 2,
+		RequestTimeout:   200 * time.Millisecond,
+	}, func(cause error) { t.Errorf("worker shut down: %v", cause) })
+	require.NoError(t, err)
+	sh.retryStrategy = retry.Fixed(0)
+	defer sh.resourceCancel()
+
+	require.NoError(t, sh.onProcessMarkerPrice())
+	skyeye.mu.Lock()
+	require.Equal(t, 2, skyeye.maxInFlight)
+	skyeye.mu.Unlock()
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		_, err := db.TokenPrice.QueryTokenPrices(name, "USD")
+		require.NoError(t, err)
+	}
+	// every attempt of slow timed out
+	_, err = db.TokenPrice.QueryTokenPrices("slow", "USD")
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	require.Equal(t, skyeyeAttempts, skyeye.count("slow"))
+}
+
+func TestWorkerUsesBatchEndpoint(t *testing.T) {
+	server := skyeyetest.NewServer(t, "skyeye/testdata")
+	db := database.NewMemoryDB()
+	sh, err := NewWorkerHandle(db, nil, &WorkerHandleConfig{
+		BaseUrl:         server.URL,
+		SymbolList:      []Symbols{{Name: "btc", Decimal: 8}, {Name: "eth", Decimal: 18}, {Name: "pepe", Decimal: 18}},
+		QuoteCurrencies: []string{"USD"},
+	}, func(cause error) { t.Errorf("worker shut down: %v", cause) })
+	require.NoError(t, err)
+	sh.retryStrategy = retry.Fixed(0)
+	defer sh.resourceCancel()
+
+	require.NoError(t, sh.onProcessMarkerPrice())
+	require.True(t, sh.client.BatchSupported())
+	btc, err := db.TokenPrice.QueryTokenPrices("btc", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "67012.1", btc.MarketPriceString())
+	require.Equal(t, "BTC", btc.TokenName)
+	eth, err := db.TokenPrice.QueryTokenPrices("eth", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "3012.45", eth.MarketPriceString())
+	// the zero price in the batch is left out
+	_, err = db.TokenPrice.QueryTokenPrices("pepe", "USD")
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestWorkerQueriesSkyeyeSymbol(t *testing.T) {
+	server := skyeyetest.NewServer(t, "skyeye/testdata")
+	db := database.NewMemoryDB()
+	sh, err := NewWorkerHandle(db, nil, &WorkerHandleConfig{
+		BaseUrl:         server.URL,
+		SymbolList:      []Symbols{{Name: "ether", Decimal: 18}},
+		SymbolMap:       symbols.NewMap([]config.Symbols{{Name: "ether", Decimal: 18, SkeyeSymbol: "eth"}}),
+		QuoteCurrencies: []string{"USD"},
+	}, func(cause error) { t.Errorf("worker shut down: %v", cause) })
+	require.NoError(t, err)
+	sh.retryStrategy = retry.Fixed(0)
+	defer sh.resourceCancel()
+
+	require.NoError(t, sh.onProcessMarkerPrice())
+	tokenPrice, err := db.TokenPrice.QueryTokenPrices("ether", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "3012.45", tokenPrice.MarketPriceString())
+	require.Equal(t, "eth", tokenPrice.SkeyeSymbol)
+}